@@ -0,0 +1,72 @@
+// flightpath solves a JSON list of legs from a file or stdin the way
+// POST /calculate?stream=true does, reading the legs as it goes so lists
+// with millions of legs don't have to fit in memory twice.
+//
+//	flightpath legs.json
+//	cat legs.json | flightpath
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+func main() {
+	airportsFile := flag.String("airports", "", "OpenFlights airports.dat to use instead of the embedded airports")
+	distance := flag.Bool("distance", false, "add the great-circle distance of every leg and the whole trip")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [legs.json]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	airportDB := airports.Default()
+	if *airportsFile != "" {
+		var err error
+		airportDB, err = airports.LoadFile(*airportsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var input io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	flightInput, flightOutput, err := models.SolveStream(input, airportDB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *distance {
+		trip, err := flightInput.Distance(flightOutput.LegOrder, airportDB)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		flightOutput.Distance = &trip
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(flightOutput)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,230 @@
+// Package airports is the reference data for the airports flights can use,
+// loaded from OpenFlights style airports.dat files.
+package airports
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// embeddedAirports is a curated set of under a hundred major airports in the
+// OpenFlights airports.dat format rather than the whole file, most regional
+// airports aren't in it. A full airports.dat can be loaded with LoadFile instead.
+//
+//go:embed airports.dat
+var embeddedAirports []byte
+
+// Airport is one row of an airports.dat file
+type Airport struct {
+	// IATA is the 3 letter code, e.g. SFO
+	IATA string
+	// ICAO is the 4 letter code, e.g. KSFO
+	ICAO      string
+	Name      string
+	City      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+	// Timezone is the IANA time zone name, e.g. America/Los_Angeles
+	Timezone string
+}
+
+// DB looks airports up by their IATA or ICAO code. A nil *DB has no
+// airports, so callers that take one optionally can pass it along as is.
+type DB struct {
+	airports []Airport
+	byIATA   map[string]int
+	byICAO   map[string]int
+	// curated is set on the embedded airports, see Curated
+	curated bool
+}
+
+// NewDB indexes airports by their codes, later airports win when codes collide
+func NewDB(airports []Airport) *DB {
+	db := &DB{
+		airports: airports,
+		byIATA:   make(map[string]int, len(airports)),
+		byICAO:   make(map[string]int, len(airports)),
+	}
+	for i, airport := range airports {
+		if airport.IATA != "" {
+			db.byIATA[airport.IATA] = i
+		}
+		if airport.ICAO != "" {
+			db.byICAO[airport.ICAO] = i
+		}
+	}
+	return db
+}
+
+// airports.dat columns, there are more after Timezone that we don't use
+const (
+	columnID = iota
+	columnName
+	columnCity
+	columnCountry
+	columnIATA
+	columnICAO
+	columnLatitude
+	columnLongitude
+	columnAltitude
+	columnUTCOffset
+	columnDST
+	columnTimezone
+	minimumColumns
+)
+
+// airports.dat uses \N for a missing value
+const nullValue = `\N`
+
+// Load reads an OpenFlights style airports.dat CSV, rows without
+// either an IATA or an ICAO code are skipped
+func Load(r io.Reader) (*DB, error) {
+	reader := csv.NewReader(r)
+	// older airports.dat files have fewer trailing columns
+	reader.FieldsPerRecord = -1
+	// a handful of names in the real file have stray quotes
+	reader.LazyQuotes = true
+
+	airports := []Airport{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < minimumColumns {
+			return nil, fmt.Errorf("airports line %d: expected at least %d columns, got %d", line, minimumColumns, len(record))
+		}
+		for i, field := range record {
+			if field == nullValue {
+				record[i] = ""
+			}
+		}
+
+		airport := Airport{
+			IATA:     record[columnIATA],
+			ICAO:     record[columnICAO],
+			Name:     record[columnName],
+			City:     record[columnCity],
+			Country:  record[columnCountry],
+			Timezone: record[columnTimezone],
+		}
+		if airport.IATA == "" && airport.ICAO == "" {
+			continue
+		}
+		airport.Latitude, err = strconv.ParseFloat(record[columnLatitude], 64)
+		if err != nil {
+			return nil, fmt.Errorf("airports line %d: invalid latitude: %w", line, err)
+		}
+		airport.Longitude, err = strconv.ParseFloat(record[columnLongitude], 64)
+		if err != nil {
+			return nil, fmt.Errorf("airports line %d: invalid longitude: %w", line, err)
+		}
+		airports = append(airports, airport)
+	}
+	return NewDB(airports), nil
+}
+
+// LoadFile reads an airports.dat file from disk
+func LoadFile(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+var (
+	defaultDB     *DB
+	defaultDBOnce sync.Once
+)
+
+// Default returns the DB of the curated airports embedded in this package
+func Default() *DB {
+	defaultDBOnce.Do(func() {
+		db, err := Load(bytes.NewReader(embeddedAirports))
+		if err != nil {
+			// the embedded file is covered by tests, so this can't happen at runtime
+			panic(fmt.Sprintf("unable to load embedded airports: %v", err))
+		}
+		db.curated = true
+		defaultDB = db
+	})
+	return defaultDB
+}
+
+// Curated reports whether db is only the curated set of major airports
+// embedded in this package, so a code missing from it may still be a real
+// airport
+func (db *DB) Curated() bool {
+	return db != nil && db.curated
+}
+
+// Lookup finds an airport by its IATA code, or failing that its ICAO code
+func (db *DB) Lookup(code string) (Airport, bool) {
+	airport, ok := db.ByIATA(code)
+	if ok {
+		return airport, true
+	}
+	return db.ByICAO(code)
+}
+
+// ByIATA finds an airport by its 3 letter IATA code
+func (db *DB) ByIATA(code string) (Airport, bool) {
+	if db == nil {
+		return Airport{}, false
+	}
+	i, ok := db.byIATA[code]
+	if !ok {
+		return Airport{}, false
+	}
+	return db.airports[i], true
+}
+
+// ByICAO finds an airport by its 4 letter ICAO code
+func (db *DB) ByICAO(code string) (Airport, bool) {
+	if db == nil {
+		return Airport{}, false
+	}
+	i, ok := db.byICAO[code]
+	if !ok {
+		return Airport{}, false
+	}
+	return db.airports[i], true
+}
+
+// Known reports whether code is the IATA or ICAO code of an airport in db
+func (db *DB) Known(code string) bool {
+	_, ok := db.Lookup(code)
+	return ok
+}
+
+// All returns every airport in db in the order they were loaded
+func (db *DB) All() []Airport {
+	if db == nil {
+		return []Airport{}
+	}
+	all := make([]Airport, len(db.airports))
+	copy(all, db.airports)
+	return all
+}
+
+// Len returns how many airports are in db
+func (db *DB) Len() int {
+	if db == nil {
+		return 0
+	}
+	return len(db.airports)
+}
@@ -0,0 +1,109 @@
+package airports_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	db := airports.Default()
+	assert.Greater(t, db.Len(), 50)
+
+	sfo, ok := db.Lookup("SFO")
+	assert.True(t, ok)
+	assert.Equal(t, "KSFO", sfo.ICAO)
+	assert.Equal(t, "San Francisco", sfo.City)
+	assert.Equal(t, "United States", sfo.Country)
+	assert.Equal(t, "America/Los_Angeles", sfo.Timezone)
+	assert.InDelta(t, 37.619, sfo.Latitude, 0.01)
+	assert.InDelta(t, -122.375, sfo.Longitude, 0.01)
+
+	// ICAO codes work with Lookup too
+	byICAO, ok := db.Lookup("KSFO")
+	assert.True(t, ok)
+	assert.Equal(t, sfo, byICAO)
+
+	_, ok = db.Lookup("hello")
+	assert.False(t, ok)
+	assert.False(t, db.Known("world"))
+
+	// only a curated set of airports is embedded
+	assert.True(t, db.Curated())
+	assert.True(t, db.Known("RDU"))
+	assert.True(t, db.Known("KOAK"))
+}
+
+func TestDefaultTimezones(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// every embedded airport needs both codes and a time zone go knows about
+	for _, airport := range airports.Default().All() {
+		assert.Len(t, airport.IATA, 3, airport.Name)
+		assert.Len(t, airport.ICAO, 4, airport.Name)
+		_, err := time.LoadLocation(airport.Timezone)
+		assert.Nil(t, err, airport.Name)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the second row has no IATA code, the third has neither code and is skipped
+	data := `1,"Goroka Airport","Goroka","Papua New Guinea","GKA","AYGA",-6.081689834590001,145.391998291,5282,10,"U","Pacific/Port_Moresby","airport","OurAirports"
+2,"Some Airfield","Somewhere","Nowhere",\N,"XXXX",1.5,2.5,0,0,"U",\N
+3,"Heliport","Somewhere","Nowhere",\N,\N,1.5,2.5,0,0,"U",\N
+`
+	db, err := airports.Load(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, db.Len())
+	assert.False(t, db.Curated())
+
+	goroka, ok := db.ByIATA("GKA")
+	assert.True(t, ok)
+	assert.Equal(t, "AYGA", goroka.ICAO)
+	assert.Equal(t, "Pacific/Port_Moresby", goroka.Timezone)
+
+	airfield, ok := db.ByICAO("XXXX")
+	assert.True(t, ok)
+	assert.Equal(t, "", airfield.IATA)
+	assert.Equal(t, "", airfield.Timezone)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	_, err := airports.Load(strings.NewReader(`1,"Goroka Airport","Goroka"` + "\n"))
+	assert.ErrorContains(t, err, "expected at least 12 columns")
+
+	_, err = airports.Load(strings.NewReader(`1,"Goroka Airport","Goroka","Papua New Guinea","GKA","AYGA",north,145.39,5282,10,"U","Pacific/Port_Moresby"` + "\n"))
+	assert.ErrorContains(t, err, "airports line 1: invalid latitude")
+}
+
+func TestNilDB(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// a nil DB is an empty one rather than a panic
+	var db *airports.DB
+	assert.False(t, db.Curated())
+	assert.False(t, db.Known("SFO"))
+	_, ok := db.Lookup("KSFO")
+	assert.False(t, ok)
+	assert.Empty(t, db.All())
+	assert.Equal(t, 0, db.Len())
+}
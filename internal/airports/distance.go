@@ -0,0 +1,24 @@
+package airports
+
+import "math"
+
+// earthRadiusKilometers is the mean radius of the earth
+const earthRadiusKilometers = 6371.0088
+
+// Kilometers per mile and per nautical mile
+const (
+	KilometersPerMile         = 1.609344
+	KilometersPerNauticalMile = 1.852
+)
+
+// GreatCircleKilometers is the shortest distance between a and b
+// over the earth's surface, using the haversine formula
+func GreatCircleKilometers(a, b Airport) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKilometers * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,28 @@
+package airports_test
+
+import (
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGreatCircleKilometers(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	db := airports.Default()
+	sfo, _ := db.ByIATA("SFO")
+	jfk, _ := db.ByIATA("JFK")
+	lhr, _ := db.ByIATA("LHR")
+	syd, _ := db.ByIATA("SYD")
+
+	assert.InDelta(t, 4152, airports.GreatCircleKilometers(sfo, jfk), 10)
+	assert.InDelta(t, 5540, airports.GreatCircleKilometers(jfk, lhr), 10)
+	assert.InDelta(t, 17016, airports.GreatCircleKilometers(lhr, syd), 20)
+
+	// same distance both ways, none to itself
+	assert.InDelta(t, airports.GreatCircleKilometers(jfk, sfo), airports.GreatCircleKilometers(sfo, jfk), 0.001)
+	assert.Zero(t, airports.GreatCircleKilometers(sfo, sfo))
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// BatchEntry is one passenger's legs in a batch, the legs are only read
+// when they're solved so legs that aren't valid only fail their own entry
+type BatchEntry struct {
+	Key  string          `json:"key"`
+	Legs json.RawMessage `json:"legs"`
+}
+
+// BatchRequest is the body of /calculate/batch, its itineraries are either
+// an object of key -> legs or a list of {"key": ..., "legs": ...} entries
+type BatchRequest struct {
+	// Itineraries are in the order they were given either way
+	Itineraries []BatchEntry
+}
+
+// UnmarshalJSON reads either form of BatchRequest.Itineraries,
+// keeping the order of an object's keys
+func (br *BatchRequest) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Itineraries json.RawMessage `json:"itineraries"`
+	}{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	itineraries := bytes.TrimSpace(raw.Itineraries)
+	if len(itineraries) > 0 && itineraries[0] == '[' {
+		entries := []BatchEntry{}
+		err = json.Unmarshal(itineraries, &entries)
+		if err != nil {
+			return err
+		}
+		br.Itineraries = entries
+		return br.checkKeys()
+	}
+
+	if len(itineraries) == 0 || bytes.Equal(itineraries, []byte("null")) {
+		br.Itineraries = nil
+		return nil
+	}
+
+	// walk the object by hand, a map would lose the order of the keys
+	dec := json.NewDecoder(bytes.NewReader(itineraries))
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("itineraries must be an object of key -> legs or a list of {\"key\", \"legs\"} entries")
+	}
+	br.Itineraries = []BatchEntry{}
+	for dec.More() {
+		token, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		entry := BatchEntry{Key: token.(string)}
+		err = dec.Decode(&entry.Legs)
+		if err != nil {
+			return err
+		}
+		br.Itineraries = append(br.Itineraries, entry)
+	}
+	return br.checkKeys()
+}
+
+// checkKeys makes sure every key is given and given only once
+func (br *BatchRequest) checkKeys() error {
+	seen := make(map[string]bool, len(br.Itineraries))
+	for i, entry := range br.Itineraries {
+		if entry.Key == "" {
+			return fmt.Errorf("itinerary %d has no key", i)
+		}
+		if seen[entry.Key] {
+			return fmt.Errorf("itinerary key %q appears more than once", entry.Key)
+		}
+		seen[entry.Key] = true
+	}
+	return nil
+}
+
+// BatchResult is the outcome for one key of a batch, exactly one of
+// Output and Problem is set
+type BatchResult struct {
+	Key string `json:"key"`
+	// Status is the status /calculate would have answered with for these legs
+	Status  int                  `json:"status"`
+	Output  *models.FlightOutput `json:"output,omitempty"`
+	Problem *Problem             `json:"problem,omitempty"`
+}
+
+// BatchResponse is the response to /calculate/batch
+type BatchResponse struct {
+	// Results are in the same order as the request's itineraries
+	Results   []BatchResult `json:"results"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+}
+
+// DefaultMaxBatchSize is how many itineraries a batch can hold unless changed
+const DefaultMaxBatchSize = 10000
+
+// BatchController serves /calculate/batch, solving every itinerary in a
+// request concurrently the same way Calculate would on its own
+type BatchController struct {
+	Calculate *CalculateController
+	// Workers is how many itineraries are solved at once
+	Workers int
+	// MaxBatchSize is how many itineraries one request can hold
+	MaxBatchSize int
+}
+
+// NewBatchController returns a BatchController solving with cc
+// on one worker per CPU
+func NewBatchController(cc *CalculateController) *BatchController {
+	return &BatchController{
+		Calculate:    cc,
+		Workers:      runtime.GOMAXPROCS(0),
+		MaxBatchSize: DefaultMaxBatchSize,
+	}
+}
+
+// ServeHTTP lets a BatchController be mounted directly on a mux
+func (bc *BatchController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bc.CalculateBatch(w, r)
+}
+
+// CalculateBatch is the handler for the /calculate/batch endpoint, it takes
+// the same query parameters as /calculate apart from stream and validateOnly
+// and applies them to every itinerary
+func (bc *BatchController) CalculateBatch(w http.ResponseWriter, r *http.Request) {
+	opts, problem := bc.Calculate.optionsFor(r)
+	if problem == nil && (opts.stream || opts.validateOnly) {
+		p := newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter",
+			fmt.Sprintf("Query parameters %s and %s can't be used with batches.", StreamQueryParam, ValidateOnlyQueryParam))
+		problem = &p
+	}
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	opts.record = true
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, "unreadable-body", "Unable to read request body", err.Error()))
+		return
+	}
+
+	batch := BatchRequest{}
+	err = json.Unmarshal(body, &batch)
+	syntaxErr := &json.SyntaxError{}
+	if errors.As(err, &syntaxErr) {
+		writeProblem(w, invalidJSONProblem(body, err))
+		return
+	}
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, "invalid-batch", "Invalid batch", err.Error()))
+		return
+	}
+	if len(batch.Itineraries) > bc.MaxBatchSize {
+		writeProblem(w, newProblem(http.StatusRequestEntityTooLarge, "batch-too-large", "Batch too large",
+			fmt.Sprintf("Batches can hold at most %d itineraries, got %d.", bc.MaxBatchSize, len(batch.Itineraries))))
+		return
+	}
+
+	response := BatchResponse{Results: bc.solveAll(batch.Itineraries, opts)}
+	for _, result := range response.Results {
+		if result.Problem != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	writeJSON(w, response)
+}
+
+// solveAll solves every entry on at most bc.Workers goroutines,
+// results are in the same order as entries
+func (bc *BatchController) solveAll(entries []BatchEntry, opts calculateOptions) []BatchResult {
+	results := make([]BatchResult, len(entries))
+	jobs := make(chan int)
+
+	workers := bc.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(entries) {
+		workers = len(entries)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for n := 0; n < workers; n++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = bc.solveOne(entries[i], opts)
+			}
+		}()
+	}
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// solveOne solves a single entry, a panic only fails that entry
+func (bc *BatchController) solveOne(entry BatchEntry, opts calculateOptions) (result BatchResult) {
+	result.Key = entry.Key
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			p := newProblem(http.StatusInternalServerError, "internal-error", "Internal server error", "Unable to calculate this itinerary, please contact support.")
+			result.Status = p.Status
+			result.Output = nil
+			result.Problem = &p
+		}
+	}()
+
+	legs := models.Legs{}
+	err := json.Unmarshal(entry.Legs, &legs)
+	if err != nil {
+		// line and column are within this entry's legs
+		p := invalidJSONProblem(entry.Legs, err)
+		result.Status = p.Status
+		result.Problem = &p
+		return result
+	}
+
+	flightOutput, problem := bc.Calculate.solve(legs, opts)
+	if problem != nil {
+		result.Status = problem.Status
+		result.Problem = problem
+		return result
+	}
+	result.Status = http.StatusOK
+	result.Output = &flightOutput
+	return result
+}
@@ -0,0 +1,117 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/tj/assert"
+)
+
+func TestCalculateBatch(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	bc := controllers.NewBatchController(cc)
+	bc.Workers = 2
+
+	// the object and list forms give the same results in the same order
+	for _, body := range []string{
+		`{"itineraries": {
+  "P1": [["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]],
+  "P2": [["SFO", "ATL"], ["IND", "EWR"]],
+  "P3": [["SLC", "JFK"], [1, "SFO"]],
+  "P4": [["SLC", "JFK"]]
+  }}`,
+		`{"itineraries": [
+  {"key": "P1", "legs": [["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]},
+  {"key": "P2", "legs": [["SFO", "ATL"], ["IND", "EWR"]]},
+  {"key": "P3", "legs": [["SLC", "JFK"], [1, "SFO"]]},
+  {"key": "P4", "legs": [["SLC", "JFK"]]}
+  ]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/calculate/batch", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		bc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		batch := controllers.BatchResponse{}
+		err := json.NewDecoder(response.Body).Decode(&batch)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		// one bad itinerary doesn't fail the rest
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, 2, batch.Succeeded)
+		assert.Equal(t, 2, batch.Failed)
+		assert.Len(t, batch.Results, 4)
+
+		assert.Equal(t, "P1", batch.Results[0].Key)
+		assert.Equal(t, http.StatusOK, batch.Results[0].Status)
+		assert.Equal(t, "SFO - ATL - GSO - IND - EWR", batch.Results[0].Output.Path)
+
+		assert.Equal(t, "P2", batch.Results[1].Key)
+		assert.Equal(t, http.StatusBadRequest, batch.Results[1].Status)
+		assert.Nil(t, batch.Results[1].Output)
+		assert.Contains(t, batch.Results[1].Problem.Type, "disconnected-segments")
+
+		assert.Equal(t, "P3", batch.Results[2].Key)
+		assert.Contains(t, batch.Results[2].Problem.Type, "invalid-json")
+
+		assert.Equal(t, "P4", batch.Results[3].Key)
+		assert.Equal(t, "SLC - JFK", batch.Results[3].Output.Path)
+	}
+}
+
+func TestCalculateBatchProblems(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	bc := controllers.NewBatchController(cc)
+	bc.MaxBatchSize = 1
+
+	for _, tc := range []struct {
+		url     string
+		body    string
+		status  int
+		problem string
+	}{
+		{url: "/calculate/batch", body: `{"itineraries": `, status: http.StatusBadRequest, problem: "invalid-json"},
+		{url: "/calculate/batch", body: `{"itineraries": "P1"}`, status: http.StatusBadRequest, problem: "invalid-batch"},
+		{url: "/calculate/batch", body: `{"itineraries": [{"key": "P1", "legs": []}, {"key": "P1", "legs": []}]}`, status: http.StatusBadRequest, problem: "invalid-batch"},
+		{url: "/calculate/batch", body: `{"itineraries": {"P1": [], "P2": []}}`, status: http.StatusRequestEntityTooLarge, problem: "batch-too-large"},
+		{url: "/calculate/batch?stream=true", body: `{}`, status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/calculate/batch?solver=nope", body: `{}`, status: http.StatusBadRequest, problem: "unknown-solver"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		bc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, tc.status, response.StatusCode, tc.body)
+		assert.True(t, strings.HasSuffix(problem.Type, "#"+tc.problem), tc.body)
+	}
+}
@@ -2,54 +2,427 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
 	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/stats"
 )
 
+// a request can pick its solver with either ?solver=name or this header,
+// the query parameter wins if both are given
+const (
+	SolverQueryParam = "solver"
+	SolverHeader     = "X-Flight-Solver"
+)
+
+// StreamQueryParam set to true solves the legs as they're read from the
+// request body instead of reading the whole body first, see models.SolveStream
+const StreamQueryParam = "stream"
+
+// ValidateOnlyQueryParam set to true reports every problem with the
+// request's legs without solving them
+const ValidateOnlyQueryParam = "validateOnly"
+
+// ValidationResult is the response to a ?validateOnly=true request with nothing wrong
+type ValidationResult struct {
+	Valid bool
+	// Legs is how many legs were checked
+	Legs int
+}
+
+// DistanceQueryParam set to true adds the great-circle distance
+// of every leg and the whole trip to the response
+const DistanceQueryParam = "distance"
+
+// AirportsQueryParam set to strict rejects airport codes that aren't in
+// the airport reference data, lenient accepts any code
+const (
+	AirportsQueryParam = "airports"
+	StrictAirports     = "strict"
+	LenientAirports    = "lenient"
+)
+
+// SplitQueryParam set to true returns every separate itinerary in the
+// request instead of failing when the legs don't all connect
+const SplitQueryParam = "split"
+
+// CalculateController serves the /calculate endpoint with solvers looked up in Solvers
+type CalculateController struct {
+	Solvers *models.SolverRegistry
+	// DefaultSolver is used when a request doesn't ask for a solver by name
+	DefaultSolver string
+	// ConnectionRules decide which layovers are too short or count as stopovers
+	ConnectionRules models.ConnectionRules
+	// Airports is the airport reference data codes are checked against
+	Airports *airports.DB
+	// StrictAirports rejects unknown airport codes unless a request asks for lenient
+	StrictAirports bool
+	// Stats counts every itinerary solved by /calculate and /calculate/batch, nil turns it off
+	Stats *stats.Aggregator
+}
+
+// NewCalculateController returns a CalculateController that falls back to defaultSolver,
+// with models.DefaultConnectionRules
+func NewCalculateController(solvers *models.SolverRegistry, defaultSolver string) *CalculateController {
+	return &CalculateController{
+		Solvers:         solvers,
+		DefaultSolver:   defaultSolver,
+		ConnectionRules: models.DefaultConnectionRules(),
+		Airports:        airports.Default(),
+	}
+}
+
+// defaultCalculateController backs CalculateHandler
+var defaultCalculateController = NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+
 // CalculateHandler is the controller for the /calculate endpoint
 // controllers should be named similarly to the routes they serve
 func CalculateHandler(w http.ResponseWriter, r *http.Request) {
-	flightInput := models.FlightsInput{}
+	defaultCalculateController.Calculate(w, r)
+}
+
+// ServeHTTP lets a CalculateController be mounted directly on a mux
+func (cc *CalculateController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cc.Calculate(w, r)
+}
+
+// Calculate is the handler for the /calculate endpoint
+func (cc *CalculateController) Calculate(w http.ResponseWriter, r *http.Request) {
+	legs := models.Legs{}
+
+	opts, problem := cc.optionsFor(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	opts.record = true
+
+	if opts.stream {
+		cc.calculateStream(w, r, opts)
+		return
+	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, "Unable to read request body")
+		writeProblem(w, newProblem(http.StatusBadRequest, "unreadable-body", "Unable to read request body", err.Error()))
 		return
 	}
 
-	err = json.Unmarshal(body, &flightInput)
+	err = json.Unmarshal(body, &legs)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, `Request body is not valid. Valid input would be: '[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]'`)
+		writeProblem(w, invalidJSONProblem(body, err))
+		return
+	}
+
+	if opts.validateOnly {
+		legs, _, problem = cc.prepare(legs, opts)
+		if problem == nil {
+			err = validate(legs, opts.solver, opts.split)
+			if err != nil {
+				p := solverProblem(legs.FlightsInput(), err)
+				problem = &p
+			}
+		}
+		if problem != nil {
+			writeProblem(w, *problem)
+			return
+		}
+		writeJSON(w, ValidationResult{Valid: true, Legs: len(legs)})
 		return
 	}
 
-	flightOutput := flightInput.FindStartAndEndFlightLinkedList()
+	flightOutput, problem := cc.solve(legs, opts)
 	fmt.Println(flightOutput.Path)
 	fmt.Printf("First departure: %s\n", flightOutput.FinalDepartureAirport)
 	fmt.Printf("Last arrival: %s\n", flightOutput.FinalArrivalAirport)
 
-	// return just the error string on an error case
-	if flightOutput.ErrorInformation != "" {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, flightOutput.ErrorInformation)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	writeJSON(w, flightOutput)
+}
+
+// calculateOptions are everything a /calculate request can ask for
+// with query parameters and headers
+type calculateOptions struct {
+	solver       models.PathSolver
+	split        bool
+	validateOnly bool
+	distance     bool
+	stream       bool
+	strict       bool
+	// record counts the solved itineraries in Stats, it's left off when
+	// solving legs that were already counted like a passenger's stored ones
+	record bool
+}
+
+// optionsFor reads the calculateOptions of r, or the problem with them
+func (cc *CalculateController) optionsFor(r *http.Request) (opts calculateOptions, problem *Problem) {
+	invalidParameter := func(err error) *Problem {
+		p := newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter", err.Error())
+		return &p
+	}
+
+	var err error
+	opts.solver, err = cc.solverFor(r)
+	if err != nil {
+		p := newProblem(http.StatusBadRequest, "unknown-solver", "Unknown solver", err.Error())
+		return opts, &p
+	}
+
+	opts.split, err = boolQueryParam(r, SplitQueryParam)
+	if err != nil {
+		return opts, invalidParameter(err)
+	}
+
+	opts.validateOnly, err = boolQueryParam(r, ValidateOnlyQueryParam)
+	if err != nil {
+		return opts, invalidParameter(err)
+	}
+
+	opts.distance, err = boolQueryParam(r, DistanceQueryParam)
+	if err != nil {
+		return opts, invalidParameter(err)
+	}
+
+	opts.stream, err = boolQueryParam(r, StreamQueryParam)
+	if err == nil && opts.stream {
+		err = streamable(r, opts.split, opts.validateOnly)
+	}
+	if err != nil {
+		return opts, invalidParameter(err)
+	}
+
+	opts.strict, err = cc.strictAirports(r)
+	if err != nil {
+		return opts, invalidParameter(err)
+	}
+	return opts, nil
+}
+
+// prepare normalizes the legs' airport codes, checks them against the
+// airport data in strict mode and puts local times in their airport's zone
+func (cc *CalculateController) prepare(legs models.Legs, opts calculateOptions) (models.Legs, []models.Normalization, *Problem) {
+	// "ksfo" and " SFO" are the same airport as "SFO"
+	legs, normalizations := legs.Normalize(cc.Airports)
+	flightInput := legs.FlightsInput()
+
+	if opts.strict {
+		err := flightInput.ValidateAirports(cc.Airports)
+		if err != nil {
+			p := solverProblem(flightInput, err)
+			return legs, normalizations, &p
+		}
+	}
+
+	// local times are in the time zone of their airport
+	legs, err := legs.ResolveTimezones(cc.Airports)
+	if err != nil {
+		p := solverProblem(flightInput, err)
+		return legs, normalizations, &p
+	}
+	return legs, normalizations, nil
+}
+
+// solve works out the FlightOutput for one set of legs, or the problem with them
+func (cc *CalculateController) solve(legs models.Legs, opts calculateOptions) (flightOutput models.FlightOutput, problem *Problem) {
+	legs, normalizations, problem := cc.prepare(legs, opts)
+	if problem != nil {
+		return flightOutput, problem
+	}
+	flightInput := legs.FlightsInput()
+
+	var err error
+	if opts.split {
+		flightOutput, err = legs.FindItineraries()
+	} else {
+		flightOutput, err = legs.Solve(opts.solver)
+	}
+
+	// point the client at the legs responsible on an error case,
+	// all of them rather than just what the solver stopped at
+	if err != nil {
+		validationErr := validate(legs, opts.solver, opts.split)
+		if validationErr != nil {
+			err = validationErr
+		}
+		p := solverProblem(flightInput, err)
+		return flightOutput, &p
+	}
+	return cc.complete(legs, normalizations, flightOutput, opts)
+}
+
+// complete adds everything a request asked for on top of the path to a
+// solved flightOutput, legs being the prepared legs it was solved from
+func (cc *CalculateController) complete(legs models.Legs, normalizations []models.Normalization, flightOutput models.FlightOutput, opts calculateOptions) (models.FlightOutput, *Problem) {
+	flightInput := legs.FlightsInput()
+	flightOutput.Layovers = legs.Layovers(flightOutput.LegOrder, cc.ConnectionRules)
+	flightOutput.Timing = legs.Timing(flightOutput.LegOrder)
+	for i, itinerary := range flightOutput.Itineraries {
+		flightOutput.Itineraries[i].Timing = legs.Timing(itinerary.LegIndexes)
+	}
+	if len(normalizations) > 0 {
+		flightOutput.Normalizations = normalizations
+	}
+	if opts.distance {
+		err := cc.addDistances(flightInput, &flightOutput)
+		if err != nil {
+			p := solverProblem(flightInput, err)
+			return flightOutput, &p
+		}
+	}
+	if opts.record && cc.Stats != nil {
+		cc.Stats.RecordOutput(flightInput, flightOutput)
+	}
+	return flightOutput, nil
+}
+
+// solverFor picks the solver asked for by the request, or the controller's default
+func (cc *CalculateController) solverFor(r *http.Request) (models.PathSolver, error) {
+	name := requestedSolver(r)
+	if name == "" {
+		name = cc.DefaultSolver
+	}
+
+	solver, ok := cc.Solvers.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("Unknown solver %q. Available solvers are: %s.", name, strings.Join(cc.Solvers.Names(), ", "))
+	}
+	return solver, nil
+}
+
+// requestedSolver is the solver the request asked for by name, if any
+func requestedSolver(r *http.Request) string {
+	name := r.URL.Query().Get(SolverQueryParam)
+	if name == "" {
+		name = r.Header.Get(SolverHeader)
+	}
+	return name
+}
+
+// streamable checks a ?stream=true request doesn't ask for anything streaming can't do
+func streamable(r *http.Request, split, validateOnly bool) error {
+	if split || validateOnly {
+		return fmt.Errorf("Query parameter %s can't be combined with %s or %s.", StreamQueryParam, SplitQueryParam, ValidateOnlyQueryParam)
+	}
+	name := requestedSolver(r)
+	if name != "" && name != models.HashMapSolver {
+		return fmt.Errorf("Query parameter %s only works with the %s solver, got %q.", StreamQueryParam, models.HashMapSolver, name)
+	}
+	return nil
+}
+
+// calculateStream solves the request body as it's read, the legs' airports
+// are all that's kept so times and everything else about a leg are ignored
+func (cc *CalculateController) calculateStream(w http.ResponseWriter, r *http.Request, opts calculateOptions) {
+	flightInput, flightOutput, err := models.SolveStream(r.Body, cc.Airports)
+	decodeErr := &models.LegDecodeError{}
+	if errors.As(err, &decodeErr) {
+		writeProblem(w, streamJSONProblem(decodeErr))
+		return
+	}
+
+	if opts.strict {
+		airportsErr := flightInput.ValidateAirports(cc.Airports)
+		if airportsErr != nil {
+			writeProblem(w, solverProblem(flightInput, airportsErr))
+			return
+		}
+	}
+	if err != nil {
+		writeProblem(w, solverProblem(flightInput, err))
 		return
 	}
 
-	jsonOut, err := json.Marshal(flightOutput)
+	if opts.distance {
+		err = cc.addDistances(flightInput, &flightOutput)
+		if err != nil {
+			writeProblem(w, solverProblem(flightInput, err))
+			return
+		}
+	}
+	if opts.record && cc.Stats != nil {
+		cc.Stats.RecordOutput(flightInput, flightOutput)
+	}
+	writeJSON(w, flightOutput)
+}
+
+// writeJSON serializes v as a successful response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	jsonOut, err := json.Marshal(v)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprint(w, `Unable to serialize flightOutput JSON, please contact support.`)
+		writeProblem(w, newProblem(http.StatusInternalServerError, "internal-error", "Internal server error", "Unable to serialize flightOutput JSON, please contact support."))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_, err = w.Write(jsonOut)
 	if err != nil {
 		panic("unable to write out JSON to client")
 	}
 }
+
+// validate reports every problem with legs that would stop them being solved,
+// legs that don't all connect are fine when they're being split into itineraries
+func validate(legs models.Legs, solver models.PathSolver, split bool) error {
+	if split {
+		return legs.ValidateItineraries()
+	}
+	return legs.Validate(solver)
+}
+
+// addDistances fills in the distance of the path and of every itinerary,
+// solvers that don't report a LegOrder get no distance
+func (cc *CalculateController) addDistances(fi models.FlightsInput, fo *models.FlightOutput) error {
+	if len(fo.LegOrder) > 0 {
+		trip, err := fi.Distance(fo.LegOrder, cc.Airports)
+		if err != nil {
+			return err
+		}
+		fo.Distance = &trip
+	}
+	for i := range fo.Itineraries {
+		trip, err := fi.Distance(fo.Itineraries[i].LegIndexes, cc.Airports)
+		if err != nil {
+			return err
+		}
+		fo.Itineraries[i].Distance = &trip
+	}
+	return nil
+}
+
+// strictAirports reports whether unknown airport codes should be rejected,
+// from ?airports=strict|lenient or the controller's default
+func (cc *CalculateController) strictAirports(r *http.Request) (bool, error) {
+	switch mode := r.URL.Query().Get(AirportsQueryParam); mode {
+	case "":
+		return cc.StrictAirports, nil
+	case StrictAirports:
+		return true, nil
+	case LenientAirports:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Query parameter %s must be %s or %s, got %q.", AirportsQueryParam, StrictAirports, LenientAirports, mode)
+	}
+}
+
+// boolQueryParam parses ?name=true style flags, a missing parameter is false
+func boolQueryParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("Query parameter %s must be true or false, got %q.", name, value)
+	}
+	return b, nil
+}
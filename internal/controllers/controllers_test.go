@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
 	"github.com/SophisticaSean/flight_path_calculator/internal/models"
@@ -103,3 +104,695 @@ func TestCalculate(t *testing.T) {
 	// ensure our path is correct
 	assert.Equal(t, "SLC - JFK - SFO - ABS", flightOutput.Path)
 }
+
+func TestCalculateSolverSelection(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// register fakes so we can tell which solver handled the request
+	solvers := models.NewSolverRegistry()
+	for _, name := range []string{"first", "second", "third"} {
+		path := name
+		solvers.Register(name, models.PathSolverFunc(func(fi models.FlightsInput) (models.FlightOutput, error) {
+			return models.FlightOutput{Path: path}, nil
+		}))
+	}
+	cc := controllers.NewCalculateController(solvers, "first")
+
+	cases := []struct {
+		target string
+		header string
+		want   string
+	}{
+		{target: "/calculate", want: "first"},
+		{target: "/calculate?solver=second", want: "second"},
+		{target: "/calculate", header: "third", want: "third"},
+		// query parameter wins over the header
+		{target: "/calculate?solver=second", header: "third", want: "second"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.target, strings.NewReader(`[["SLC", "JFK"]]`))
+		if c.header != "" {
+			req.Header.Set(controllers.SolverHeader, c.header)
+		}
+		w := httptest.NewRecorder()
+
+		// handle the request
+		cc.ServeHTTP(w, req)
+
+		response := w.Result()
+		flightOutput := models.FlightOutput{}
+		err := json.NewDecoder(response.Body).Decode(&flightOutput)
+		response.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, c.want, flightOutput.Path)
+	}
+}
+
+func TestCalculateUnknownSolver(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["SLC", "JFK"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?solver=quantum", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, problem.Detail, `Unknown solver "quantum"`)
+	assert.Contains(t, problem.Detail, "eulerian, hashmap, linkedlist, naive")
+}
+
+func TestCalculateProblemInvalidJSONPosition(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the missing comma after the first leg is noticed where the second leg starts
+	body := strings.NewReader("[\n  [\"SLC\", \"JFK\"]\n  [\"JFK\", \"SFO\"]\n]")
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, controllers.ProblemContentType, response.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Contains(t, problem.Type, "invalid-json")
+	assert.Equal(t, 3, problem.Line)
+	assert.Equal(t, 3, problem.Column)
+}
+
+func TestCalculateProblemNotALeg(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader("[\n  [\"SLC\", \"JFK\"],\n  1\n]")
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	// the detail doesn't leak Go types and still points at the leg
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, problem.Type, "invalid-json")
+	assert.Contains(t, problem.Detail, "leg must be a [from, to] pair of airport codes or an object")
+	assert.NotContains(t, problem.Detail, "struct")
+	assert.Equal(t, 3, problem.Line)
+	assert.Equal(t, 3, problem.Column)
+}
+
+func TestCalculateProblemInvalidLegs(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["SLC", "JFK"], ["JFK", "SFO"], ["ATL", "SFO"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, controllers.ProblemContentType, response.Header.Get("Content-Type"))
+	assert.Contains(t, problem.Type, "duplicate-arrival")
+	assert.Equal(t, "Duplicate arrival airport", problem.Title)
+	assert.Equal(t, []controllers.InvalidLeg{
+		{Index: 1, Leg: []string{"JFK", "SFO"}, Reason: "arrives at SFO"},
+		{Index: 2, Leg: []string{"ATL", "SFO"}, Reason: "arrives at SFO"},
+	}, problem.InvalidLegs)
+}
+
+func TestCalculateSplitItineraries(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["SLC", "JFK"], ["SFO", "ATL"], ["JFK", "BOS"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?split=true", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, flightOutput.Itineraries, 2)
+	assert.Equal(t, "SLC - JFK - BOS", flightOutput.Itineraries[0].Path)
+	assert.Equal(t, []int{0, 2}, flightOutput.Itineraries[0].LegIndexes)
+	assert.Equal(t, "SFO - ATL", flightOutput.Itineraries[1].Path)
+	assert.Equal(t, []int{1}, flightOutput.Itineraries[1].LegIndexes)
+}
+
+func TestCalculateSplitTimedRoundTrip(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// a round trip and a later trip, the times tell them apart
+	sample := `[
+  {"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00"},
+  {"from": "SLC", "to": "JFK", "departure": "2026-10-10T08:00:00-06:00"},
+  {"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00-05:00"}
+  ]`
+	for _, url := range []string{"/calculate?split=true", "/calculate?split=true&validateOnly=true"} {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(sample))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		controllers.CalculateHandler(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode, url)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/calculate?split=true", strings.NewReader(sample))
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Len(t, flightOutput.Itineraries, 2)
+	assert.Equal(t, "SFO - ORD - SFO", flightOutput.Itineraries[0].Path)
+	assert.Equal(t, []int{0, 2}, flightOutput.Itineraries[0].LegIndexes)
+	assert.Equal(t, "SLC - JFK", flightOutput.Itineraries[1].Path)
+}
+
+func TestCalculateTimestampedLegs(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the second leg departs before the first one lands
+	body := strings.NewReader(`[
+  {"from": "JFK", "to": "SFO", "departure": "2026-10-01T12:00:00Z", "arrival": "2026-10-01T18:00:00Z"},
+  {"from": "SLC", "to": "JFK", "departure": "2026-10-01T08:00:00Z", "arrival": "2026-10-01T12:30:00Z"}
+  ]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SLC - JFK - SFO", flightOutput.Path)
+	assert.Equal(t, []int{1, 0}, flightOutput.LegOrder)
+	assert.Len(t, flightOutput.TimingConflicts, 1)
+	assert.Equal(t, models.DepartsBeforePreviousArrival, flightOutput.TimingConflicts[0].Kind)
+}
+
+func TestCalculateLegMetadata(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// objects and legacy pairs can be mixed
+	body := strings.NewReader(`[
+  {"from": "SFO", "to": "ATL", "flight": "DL1234", "carrier": "DL", "passenger": "P123", "source": "agencyA"},
+  ["ATL", "EWR"]
+  ]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?split=true", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SFO - ATL - EWR", flightOutput.Path)
+	assert.Len(t, flightOutput.OrderedLegs, 2)
+	assert.Equal(t, "DL1234", flightOutput.OrderedLegs[0].Flight)
+	assert.Equal(t, "agencyA", flightOutput.OrderedLegs[0].Source)
+	assert.Equal(t, "ATL", flightOutput.OrderedLegs[1].From)
+	assert.Equal(t, flightOutput.OrderedLegs, flightOutput.Itineraries[0].Legs)
+}
+
+func TestCalculateLayovers(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	cc.ConnectionRules.Airports = map[string]models.Duration{"JFK": models.Duration(time.Hour)}
+
+	body := strings.NewReader(`[
+  {"from": "SLC", "to": "JFK", "departure": "2026-10-01T08:00:00Z", "arrival": "2026-10-01T12:30:00Z"},
+  {"from": "JFK", "to": "SFO", "departure": "2026-10-01T13:00:00Z", "arrival": "2026-10-01T19:00:00Z"}
+  ]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	cc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, flightOutput.Layovers, 1)
+	assert.Equal(t, "JFK", flightOutput.Layovers[0].Airport)
+	assert.Equal(t, models.Duration(30*time.Minute), flightOutput.Layovers[0].Duration)
+	assert.True(t, flightOutput.Layovers[0].BelowMinimumConnection)
+	assert.False(t, flightOutput.Layovers[0].Stopover)
+}
+
+func TestCalculateStrictAirports(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	cc.StrictAirports = true
+
+	// strict by default, so made up codes are rejected
+	req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(`[["hello", "world"]]`))
+	w := httptest.NewRecorder()
+	cc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.True(t, strings.HasSuffix(problem.Type, "#unknown-airport"))
+	assert.Len(t, problem.InvalidLegs, 1)
+	assert.Equal(t, 0, problem.InvalidLegs[0].Index)
+
+	// unless the request asks for lenient
+	req = httptest.NewRequest(http.MethodPost, "/calculate?airports=lenient", strings.NewReader(`[["hello", "world"]]`))
+	w = httptest.NewRecorder()
+	cc.ServeHTTP(w, req)
+
+	lenientResponse := w.Result()
+	defer lenientResponse.Body.Close()
+
+	flightOutput := models.FlightOutput{}
+	err = json.NewDecoder(lenientResponse.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, lenientResponse.StatusCode)
+	assert.Equal(t, []string{"HELLO", "WORLD"}, flightOutput.CalculateResult)
+}
+
+func TestCalculateNormalization(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the same airport three ways, which used to be three different airports
+	body := strings.NewReader(`[["IND", "sfo"], [" SFO ", "ATL"], ["ATL", "KEWR"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?airports=strict", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "IND - SFO - ATL - EWR", flightOutput.Path)
+	assert.Equal(t, []models.Normalization{
+		{LegIndex: 0, Field: "to", Original: "sfo", Normalized: "SFO"},
+		{LegIndex: 1, Field: "from", Original: " SFO ", Normalized: "SFO"},
+		{LegIndex: 2, Field: "to", Original: "KEWR", Normalized: "EWR"},
+	}, flightOutput.Normalizations)
+}
+
+func TestCalculateDistance(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["JFK", "LHR"], ["SFO", "JFK"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?distance=true", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotNil(t, flightOutput.Distance)
+	assert.Len(t, flightOutput.Distance.Legs, 2)
+	assert.Equal(t, 1, flightOutput.Distance.Legs[0].LegIndex)
+	assert.True(t, flightOutput.Distance.Total.Kilometers > 9600)
+	assert.True(t, flightOutput.Distance.Total.Kilometers < 9800)
+
+	// distance is opt in
+	req = httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(`[["JFK", "LHR"], ["SFO", "JFK"]]`))
+	w = httptest.NewRecorder()
+	controllers.CalculateHandler(w, req)
+
+	withoutResponse := w.Result()
+	defer withoutResponse.Body.Close()
+
+	withoutOutput := models.FlightOutput{}
+	err = json.NewDecoder(withoutResponse.Body).Decode(&withoutOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+	assert.Nil(t, withoutOutput.Distance)
+}
+
+func TestCalculateLocalTimes(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// local times across the date line, 13 hours in the air and 3 on the ground
+	body := strings.NewReader(`[
+  {"from": "LAX", "to": "JFK", "departure": "2026-10-01T09:00:00", "arrival": "2026-10-01T17:30:00"},
+  {"from": "SYD", "to": "LAX", "departure": "2026-10-01T10:00:00", "arrival": "2026-10-01T06:00:00"}
+  ]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SYD - LAX - JFK", flightOutput.Path)
+	assert.Empty(t, flightOutput.TimingConflicts)
+	assert.Equal(t, models.Duration(3*time.Hour), flightOutput.Layovers[0].Duration)
+	assert.Equal(t, models.Duration(13*time.Hour), flightOutput.Timing.Legs[0].BlockTime)
+	assert.Equal(t, models.Duration(21*time.Hour+30*time.Minute), *flightOutput.Timing.Elapsed)
+
+	// the times are echoed back with their airport's offset
+	assert.Equal(t, "2026-10-01T10:00:00+10:00", flightOutput.OrderedLegs[0].Departure.Format(time.RFC3339))
+}
+
+func TestCalculateProblemGaps(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the agency never sent ATL -> IND
+	body := strings.NewReader(`[["SFO", "ATL"], ["IND", "EWR"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	problem := controllers.Problem{}
+	err := json.NewDecoder(response.Body).Decode(&problem)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Contains(t, problem.Type, "disconnected-segments")
+	assert.Equal(t, []controllers.ProblemGap{{
+		Arrival:           "ATL",
+		Departure:         "IND",
+		ArrivingLegIndex:  0,
+		DepartingLegIndex: 1,
+		Detail:            "Segment ending at ATL and segment starting at IND are not connected.",
+	}}, problem.Gaps)
+	assert.Equal(t, [][]string{{"ATL", "IND"}}, problem.SuggestedLegs)
+}
+
+func TestCalculateEveryProblem(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// two malformed legs and a duplicate departure in one response
+	body := `[["SFO"], ["SFO", "ATL"], ["ATL", "IND"], ["ATL", "EWR"], ["IND"]]`
+	for _, url := range []string{"/calculate", "/calculate?validateOnly=true"} {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		controllers.CalculateHandler(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, problem.Type, "validation-failed")
+		assert.Len(t, problem.Problems, 3)
+		assert.Contains(t, problem.Problems[0].Type, "malformed-leg")
+		assert.Contains(t, problem.Problems[1].Type, "malformed-leg")
+		assert.Contains(t, problem.Problems[2].Type, "duplicate-departure")
+		assert.Equal(t, []controllers.InvalidLeg{
+			{Index: 0, Leg: []string{"SFO"}, Reason: "leg does not have exactly two airports"},
+			{Index: 4, Leg: []string{"IND"}, Reason: "leg does not have exactly two airports"},
+			{Index: 2, Leg: []string{"ATL", "IND"}, Reason: "departs from ATL"},
+			{Index: 3, Leg: []string{"ATL", "EWR"}, Reason: "departs from ATL"},
+		}, problem.InvalidLegs)
+	}
+}
+
+func TestCalculateEmptyInput(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// every solver answers the same, whether or not it could cope with no legs itself
+	urls := []string{"/calculate", "/calculate?split=true", "/calculate?validateOnly=true", "/calculate?stream=true"}
+	for _, name := range models.NewDefaultSolverRegistry().Names() {
+		urls = append(urls, "/calculate?solver="+name)
+	}
+	for _, url := range urls {
+		for _, body := range []string{`[]`, `null`} {
+			req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			// handle the request
+			controllers.CalculateHandler(w, req)
+
+			response := w.Result()
+			defer response.Body.Close()
+
+			problem := controllers.Problem{}
+			err := json.NewDecoder(response.Body).Decode(&problem)
+			if err != nil {
+				t.Errorf("unable to unmarshal response body")
+			}
+
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, url+" "+body)
+			assert.True(t, strings.HasSuffix(problem.Type, "#empty-input"), url+" "+body)
+		}
+	}
+}
+
+func TestCalculateValidateOnly(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?validateOnly=true", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	result := controllers.ValidationResult{}
+	err := json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, controllers.ValidationResult{Valid: true, Legs: 4}, result)
+}
+
+func TestCalculateStream(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	body := strings.NewReader(`[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]`)
+	req := httptest.NewRequest(http.MethodPost, "/calculate?stream=true", body)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	controllers.CalculateHandler(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	// deserialize the body back into a models.FlightOutput
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+}
+
+func TestCalculateStreamProblems(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url     string
+		body    string
+		problem string
+	}{
+		{url: "/calculate?stream=true", body: `[["IND", "EWR"], [1, "IND"]]`, problem: "invalid-json"},
+		{url: "/calculate?stream=true", body: `[["IND", "EWR"], ["IND", "SFO"]]`, problem: "duplicate-departure"},
+		// the same as json.Unmarshal rejects without streaming
+		{url: "/calculate?stream=true", body: `[["IND", "EWR"]] garbage`, problem: "invalid-json"},
+		{url: "/calculate", body: `[["IND", "EWR"]] garbage`, problem: "invalid-json"},
+		{url: "/calculate?stream=true&split=true", body: `[]`, problem: "invalid-parameter"},
+		{url: "/calculate?stream=true&solver=linkedlist", body: `[]`, problem: "invalid-parameter"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		controllers.CalculateHandler(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.True(t, strings.HasSuffix(problem.Type, "#"+tc.problem), tc.url+" "+tc.body)
+	}
+}
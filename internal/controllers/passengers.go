@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/store"
+)
+
+// PassengersPath is where a PassengerController is mounted, it serves
+//
+//	POST /passengers/{id}/legs       append legs to the passenger's legs
+//	GET  /passengers/{id}/legs       every leg appended so far
+//	GET  /passengers/{id}/itinerary  the legs so far solved as /calculate would
+//	GET  /passengers/{id}/location   where the legs put the passenger at ?at=
+//	GET  /passengers/{id}/cotravelers  other passengers who flew the same legs
+const PassengersPath = "/passengers/"
+
+// AtQueryParam is the RFC 3339 time a location is asked for, it defaults to now
+const AtQueryParam = "at"
+
+// PassengerController keeps passengers' legs in Store between requests
+type PassengerController struct {
+	Store store.Store
+	// Calculate solves a passenger's legs, with the same query parameters as /calculate
+	Calculate *CalculateController
+
+	mu sync.Mutex
+	// passenger -> their legs joined into chains, built from Store the
+	// first time they're needed and kept up to date as legs are appended.
+	// nil once a leg has a time or doesn't fit the chains, from then on the
+	// passenger's legs are solved in full the way /calculate would.
+	itineraries map[string]*models.IncrementalItinerary
+}
+
+// NewPassengerController returns a PassengerController keeping legs in s and solving them with cc
+func NewPassengerController(s store.Store, cc *CalculateController) *PassengerController {
+	return &PassengerController{
+		Store:       s,
+		Calculate:   cc,
+		itineraries: make(map[string]*models.IncrementalItinerary),
+	}
+}
+
+// AppendResult is the response to appending legs
+type AppendResult struct {
+	Passenger string
+	// Appended is how many legs this request added, Legs how many there are now
+	Appended int
+	Legs     int
+}
+
+// CoTravelersResult is the response to asking for a passenger's co-travelers
+type CoTravelersResult struct {
+	Passenger   string
+	CoTravelers []models.CoTraveler
+}
+
+// ServeHTTP routes /passengers/{id}/legs and /passengers/{id}/itinerary
+func (pc *PassengerController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PassengersPath), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeProblem(w, newProblem(http.StatusNotFound, "not-found", "Not found", fmt.Sprintf("%s is not a passenger endpoint.", r.URL.Path)))
+		return
+	}
+	passenger, resource := parts[0], parts[1]
+
+	switch {
+	case resource == "legs" && r.Method == http.MethodPost:
+		pc.AppendLegs(w, r, passenger)
+	case resource == "legs" && r.Method == http.MethodGet:
+		pc.Legs(w, r, passenger)
+	case resource == "itinerary" && r.Method == http.MethodGet:
+		pc.Itinerary(w, r, passenger)
+	case resource == "location" && r.Method == http.MethodGet:
+		pc.Location(w, r, passenger)
+	case resource == "cotravelers" && r.Method == http.MethodGet:
+		pc.CoTravelers(w, r, passenger)
+	case resource == "legs" || resource == "itinerary" || resource == "location" || resource == "cotravelers":
+		writeProblem(w, newProblem(http.StatusMethodNotAllowed, "method-not-allowed", "Method not allowed", fmt.Sprintf("%s doesn't support %s.", r.URL.Path, r.Method)))
+	default:
+		writeProblem(w, newProblem(http.StatusNotFound, "not-found", "Not found", fmt.Sprintf("%s is not a passenger endpoint.", r.URL.Path)))
+	}
+}
+
+// AppendLegs adds the legs in the request body to passenger's legs. Legs can
+// arrive in any order and don't have to connect yet, but every one has to
+// have both its airports. Legs without times are joined onto the passenger's
+// IncrementalItinerary as they're added. Anything /calculate could still
+// solve is kept, so a timed round trip or a leg repeating an airport is
+// stored too and the passenger's legs are solved in full from then on.
+func (pc *PassengerController) AppendLegs(w http.ResponseWriter, r *http.Request, passenger string) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, "unreadable-body", "Unable to read request body", err.Error()))
+		return
+	}
+
+	legs := models.Legs{}
+	err = json.Unmarshal(body, &legs)
+	if err != nil {
+		writeProblem(w, invalidJSONProblem(body, err))
+		return
+	}
+
+	// a leg missing an airport can never be solved, so don't keep it
+	flightInput := legs.FlightsInput()
+	problems := []error{}
+	for i, flightPair := range flightInput {
+		if len(flightPair) != 2 {
+			problems = append(problems, &models.MalformedLegError{Index: i, Leg: flightPair})
+		}
+	}
+	if len(problems) == 1 {
+		writeProblem(w, solverProblem(flightInput, problems[0]))
+		return
+	}
+	if len(problems) > 1 {
+		writeProblem(w, solverProblem(flightInput, &models.ValidationError{Errors: problems}))
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	itinerary, problem := pc.itinerary(passenger)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	err = pc.Store.Append(passenger, legs)
+	if err != nil {
+		delete(pc.itineraries, passenger)
+		writeProblem(w, storeProblem(err))
+		return
+	}
+	if itinerary != nil && !pc.join(itinerary, legs) {
+		itinerary = nil
+		pc.itineraries[passenger] = nil
+	}
+
+	if itinerary == nil {
+		stored, err := pc.Store.Legs(passenger)
+		if err != nil {
+			writeProblem(w, storeProblem(err))
+			return
+		}
+		writeJSON(w, AppendResult{Passenger: passenger, Appended: len(legs), Legs: len(stored)})
+		return
+	}
+	writeJSON(w, AppendResult{Passenger: passenger, Appended: len(legs), Legs: itinerary.Len()})
+}
+
+// itinerary returns passenger's IncrementalItinerary, joining their stored
+// legs into one the first time it's asked for, or nil when their legs have
+// to be solved in full. pc.mu must be held.
+func (pc *PassengerController) itinerary(passenger string) (*models.IncrementalItinerary, *Problem) {
+	if pc.itineraries == nil {
+		pc.itineraries = make(map[string]*models.IncrementalItinerary)
+	}
+	itinerary, ok := pc.itineraries[passenger]
+	if ok {
+		return itinerary, nil
+	}
+
+	legs, err := pc.Store.Legs(passenger)
+	if errors.Is(err, store.ErrNotFound) {
+		legs, err = models.Legs{}, nil
+	}
+	if err != nil {
+		p := storeProblem(err)
+		return nil, &p
+	}
+	itinerary = models.NewIncrementalItinerary()
+	if !pc.join(itinerary, legs) {
+		itinerary = nil
+	}
+	pc.itineraries[passenger] = itinerary
+	return itinerary, nil
+}
+
+// join adds legs to itinerary. It stops at the first leg with a departure
+// time, since those can decide the order, or that the chains can't take,
+// and returns false.
+func (pc *PassengerController) join(itinerary *models.IncrementalItinerary, legs models.Legs) bool {
+	// codes are joined the way they'll be solved, "ksfo" meets "SFO"
+	legs, _ = legs.Normalize(pc.Calculate.Airports)
+	for i, flightPair := range legs.FlightsInput() {
+		if legs[i].Departure != nil {
+			return false
+		}
+		if itinerary.Add(flightPair) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Legs returns every leg appended for passenger
+func (pc *PassengerController) Legs(w http.ResponseWriter, r *http.Request, passenger string) {
+	legs, err := pc.Store.Legs(passenger)
+	if err != nil {
+		writeProblem(w, storeProblem(err))
+		return
+	}
+	writeJSON(w, legs)
+}
+
+// Itinerary answers with every leg appended for passenger so far in travel
+// order. The order comes from the passenger's IncrementalItinerary rather than
+// solving every leg again, unless the request names a solver, asks for
+// ?split=true, or the passenger has legs the chains can't order.
+func (pc *PassengerController) Itinerary(w http.ResponseWriter, r *http.Request, passenger string) {
+	opts, problem := pc.Calculate.optionsFor(r)
+	if problem == nil && (opts.stream || opts.validateOnly) {
+		p := newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter",
+			fmt.Sprintf("Query parameters %s and %s can't be used with stored legs.", StreamQueryParam, ValidateOnlyQueryParam))
+		problem = &p
+	}
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	legs, err := pc.Store.Legs(passenger)
+	if err != nil {
+		writeProblem(w, storeProblem(err))
+		return
+	}
+
+	itinerary, problem := pc.itinerary(passenger)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	if itinerary == nil || opts.split || requestedSolver(r) != "" {
+		flightOutput, problem := pc.Calculate.solve(legs, opts)
+		if problem != nil {
+			writeProblem(w, *problem)
+			return
+		}
+		writeJSON(w, flightOutput)
+		return
+	}
+
+	legs, normalizations, problem := pc.Calculate.prepare(legs, opts)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	flightOutput, err := itinerary.SolveLegs(legs)
+	if err != nil {
+		writeProblem(w, solverProblem(legs.FlightsInput(), err))
+		return
+	}
+	flightOutput, problem = pc.Calculate.complete(legs, normalizations, flightOutput, opts)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	writeJSON(w, flightOutput)
+}
+
+// Location answers where the passenger's legs put them at the time in ?at=
+func (pc *PassengerController) Location(w http.ResponseWriter, r *http.Request, passenger string) {
+	at := time.Now()
+	value := r.URL.Query().Get(AtQueryParam)
+	if value != "" {
+		var err error
+		at, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeProblem(w, newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter",
+				fmt.Sprintf("Query parameter %s must be an RFC 3339 time like 2026-10-01T12:00:00Z: %s", AtQueryParam, err)))
+			return
+		}
+	}
+
+	opts, problem := pc.Calculate.optionsFor(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	legs, err := pc.Store.Legs(passenger)
+	if err != nil {
+		writeProblem(w, storeProblem(err))
+		return
+	}
+
+	// local times have to be put in their airport's zone before they can be compared
+	legs, _, problem = pc.Calculate.prepare(legs, opts)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	writeJSON(w, legs.LocationAt(at))
+}
+
+// CoTravelers lists every other passenger who flew one of passenger's legs,
+// either the same flight number or the same route on the same day
+func (pc *PassengerController) CoTravelers(w http.ResponseWriter, r *http.Request, passenger string) {
+	opts, problem := pc.Calculate.optionsFor(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	legs, err := pc.Store.Legs(passenger)
+	if err != nil {
+		writeProblem(w, storeProblem(err))
+		return
+	}
+	legs, _, problem = pc.Calculate.prepare(legs, opts)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	passengers, err := pc.Store.Passengers()
+	if err != nil {
+		writeProblem(w, storeProblem(err))
+		return
+	}
+	others := make(map[string]models.Legs, len(passengers))
+	for _, other := range passengers {
+		if other == passenger {
+			continue
+		}
+		otherLegs, err := pc.Store.Legs(other)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			writeProblem(w, storeProblem(err))
+			return
+		}
+		// someone else's bad legs aren't this passenger's problem, skip them
+		otherLegs, _, problem = pc.Calculate.prepare(otherLegs, opts)
+		if problem != nil {
+			continue
+		}
+		others[other] = otherLegs
+	}
+
+	writeJSON(w, CoTravelersResult{Passenger: passenger, CoTravelers: models.FindCoTravelers(legs, others, pc.Calculate.Airports)})
+}
+
+// storeProblem turns an error from a store.Store into a Problem
+func storeProblem(err error) Problem {
+	if errors.Is(err, store.ErrNotFound) {
+		return newProblem(http.StatusNotFound, "unknown-passenger", "Unknown passenger", "No legs have been stored for this passenger.")
+	}
+	return newProblem(http.StatusInternalServerError, "internal-error", "Internal server error", "Unable to access stored legs, please contact support.")
+}
@@ -0,0 +1,433 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/store"
+	"github.com/tj/assert"
+)
+
+func TestPassengerItinerary(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	pc := controllers.NewPassengerController(store.NewMemoryStore(), cc)
+
+	// legs arrive a few at a time and out of order
+	for i, body := range []string{`[["IND", "EWR"], ["SFO", "ATL"]]`, `[["GSO", "IND"]]`, `[["ATL", "GSO"]]`} {
+		req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		result := controllers.AppendResult{}
+		err := json.NewDecoder(response.Body).Decode(&result)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "P1", result.Passenger)
+		assert.Equal(t, []int{2, 3, 4}[i], result.Legs)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/passengers/P1/itinerary", nil)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	flightOutput := models.FlightOutput{}
+	err := json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+
+	req = httptest.NewRequest(http.MethodGet, "/passengers/P1/legs", nil)
+	w = httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response = w.Result()
+	defer response.Body.Close()
+
+	legs := models.Legs{}
+	err = json.NewDecoder(response.Body).Decode(&legs)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	// legs come back in the order they were appended
+	assert.Equal(t, models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO", "IND"}, {"ATL", "GSO"}}, legs.FlightsInput())
+}
+
+func TestPassengerAppendNothing(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	pc := controllers.NewPassengerController(store.NewMemoryStore(), cc)
+
+	req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(`[]`))
+	w := httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	result := controllers.AppendResult{}
+	err := json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	// a new passenger with nothing appended isn't an unknown one
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, controllers.AppendResult{Passenger: "P1", Appended: 0, Legs: 0}, result)
+}
+
+func TestPassengerAppendConflicts(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+
+	for _, tc := range []struct {
+		body    string
+		problem string
+	}{
+		{body: `[["GSO", "IND"], ["ATL", "IND"]]`, problem: "validation-failed"},
+		{body: `[["ATL", "IND"]]`, problem: "duplicate-departure"},
+		{body: `[["IND", "GSO"]]`, problem: "duplicate-arrival"},
+		{body: `[["GSO", "SFO"]]`, problem: "loop"},
+		// codes are compared the way they'll be solved
+		{body: `[["katl", "IND"]]`, problem: "duplicate-departure"},
+	} {
+		s := store.NewMemoryStore()
+		err := s.Append("P1", models.Legs{{From: "SFO", To: "ATL"}, {From: "ATL", To: "GSO"}})
+		assert.Nil(t, err)
+		pc := controllers.NewPassengerController(s, cc)
+
+		// /calculate could still solve these with another solver, so they're kept
+		req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		result := controllers.AppendResult{}
+		err = json.NewDecoder(response.Body).Decode(&result)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusOK, response.StatusCode, tc.body)
+		legs, err := s.Legs("P1")
+		assert.Nil(t, err)
+		assert.Equal(t, len(legs), result.Legs, tc.body)
+
+		// and solving them in full reports the conflict
+		req = httptest.NewRequest(http.MethodGet, "/passengers/P1/itinerary", nil)
+		w = httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response = w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err = json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, tc.body)
+		assert.True(t, strings.HasSuffix(problem.Type, "#"+tc.problem), tc.body)
+	}
+}
+
+func TestPassengerRoundTrip(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+
+	// the whole trip at once, and the return leg on its own later
+	for _, bodies := range [][]string{
+		{`[{"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00", "arrival": "2026-10-01T14:10:00-05:00"},
+  {"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00-05:00", "arrival": "2026-10-03T20:30:00-07:00"}]`},
+		{`[{"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00", "arrival": "2026-10-01T14:10:00-05:00"}]`,
+			`[{"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00-05:00", "arrival": "2026-10-03T20:30:00-07:00"}]`},
+	} {
+		pc := controllers.NewPassengerController(store.NewMemoryStore(), cc)
+		for _, body := range bodies {
+			req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			pc.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode, body)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/passengers/P1/itinerary", nil)
+		w := httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		flightOutput := models.FlightOutput{}
+		err := json.NewDecoder(response.Body).Decode(&flightOutput)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		// the same answer /calculate gives
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "SFO - ORD - SFO", flightOutput.Path)
+		assert.Equal(t, []int{0, 1}, flightOutput.LegOrder)
+
+		// and the passenger can be found between the legs
+		req = httptest.NewRequest(http.MethodGet, "/passengers/P1/location?at=2026-10-02T12:00:00Z", nil)
+		w = httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response = w.Result()
+		defer response.Body.Close()
+
+		location := models.Location{}
+		err = json.NewDecoder(response.Body).Decode(&location)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, models.AtAirport, location.Kind)
+		assert.Equal(t, "ORD", location.Airport)
+	}
+}
+
+func TestPassengerIncrementalAppends(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	s := store.NewMemoryStore()
+	err := s.Append("P1", models.Legs{{From: "SFO", To: "ATL"}, {From: "ATL", To: "GSO"}})
+	assert.Nil(t, err)
+	pc := controllers.NewPassengerController(s, cc)
+
+	// legs without times are joined onto the stored chains
+	req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(`[["GSO", "IND"]]`))
+	w := httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	result := controllers.AppendResult{}
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 3, result.Legs)
+
+	req = httptest.NewRequest(http.MethodGet, "/passengers/P1/itinerary", nil)
+	w = httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response = w.Result()
+	defer response.Body.Close()
+
+	flightOutput := models.FlightOutput{}
+	err = json.NewDecoder(response.Body).Decode(&flightOutput)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SFO - ATL - GSO - IND", flightOutput.Path)
+	assert.Equal(t, []int{0, 1, 2}, flightOutput.LegOrder)
+}
+
+func TestPassengerLocation(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	pc := controllers.NewPassengerController(store.NewMemoryStore(), cc)
+
+	// SFO times are local, 08:00 in San Francisco is 15:00 UTC
+	body := `[{"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00", "arrival": "2026-10-01T16:00:00-04:00"}, ["ATL", "GSO"]]`
+	req := httptest.NewRequest(http.MethodPost, "/passengers/P1/legs", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	pc.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	for _, tc := range []struct {
+		at       string
+		kind     string
+		airport  string
+		progress float64
+	}{
+		{at: "2026-10-01T14:00:00Z", kind: models.AtAirport, airport: "SFO"},
+		{at: "2026-10-01T17:30:00Z", kind: models.InTheAir, progress: 50},
+		{at: "2026-10-01T21:00:00Z", kind: models.AtAirport, airport: "ATL"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/passengers/P1/location?at="+tc.at, nil)
+		w := httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		location := models.Location{}
+		err := json.NewDecoder(response.Body).Decode(&location)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusOK, response.StatusCode, tc.at)
+		assert.Equal(t, tc.kind, location.Kind, tc.at)
+		assert.Equal(t, tc.airport, location.Airport, tc.at)
+		assert.Equal(t, tc.progress, location.PercentComplete, tc.at)
+	}
+}
+
+func TestPassengerCoTravelers(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	pc := controllers.NewPassengerController(store.NewMemoryStore(), cc)
+
+	for passenger, body := range map[string]string{
+		"P1": `[{"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00", "flight": "DL123"}, ["ATL", "GSO"]]`,
+		"P2": `[{"from": "KSFO", "to": "ATL", "departure": "2026-10-01T08:00:00-07:00"}]`,
+		"P3": `[["IND", "EWR"]]`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/passengers/"+passenger+"/legs", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		pc.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/passengers/P1/cotravelers", nil)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	pc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	result := controllers.CoTravelersResult{}
+	err := json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	// KSFO is SFO and 08:00 in San Francisco is the same day either way
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "P1", result.Passenger)
+	assert.Len(t, result.CoTravelers, 1)
+	assert.Equal(t, "P2", result.CoTravelers[0].Passenger)
+	assert.Equal(t, 0.5, result.CoTravelers[0].Score)
+	assert.Equal(t, models.SameRoute, result.CoTravelers[0].SharedLegs[0].Match)
+}
+
+func TestPassengerProblems(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	s := store.NewMemoryStore()
+	err := s.Append("P2", models.Legs{{From: "SFO", To: "ATL"}, {From: "IND", To: "EWR"}})
+	assert.Nil(t, err)
+	pc := controllers.NewPassengerController(s, cc)
+
+	for _, tc := range []struct {
+		method  string
+		url     string
+		body    string
+		status  int
+		problem string
+	}{
+		{method: http.MethodGet, url: "/passengers/P1/itinerary", status: http.StatusNotFound, problem: "unknown-passenger"},
+		{method: http.MethodGet, url: "/passengers/P1/legs", status: http.StatusNotFound, problem: "unknown-passenger"},
+		{method: http.MethodGet, url: "/passengers/P2/itinerary", status: http.StatusBadRequest, problem: "disconnected-segments"},
+		{method: http.MethodGet, url: "/passengers/P2/itinerary?validateOnly=true", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{method: http.MethodPost, url: "/passengers/P1/legs", body: `[["IND", "EWR"`, status: http.StatusBadRequest, problem: "invalid-json"},
+		{method: http.MethodPost, url: "/passengers/P1/legs", body: `[["IND", "EWR"], ["SFO"]]`, status: http.StatusBadRequest, problem: "malformed-leg"},
+		{method: http.MethodPost, url: "/passengers/P1/itinerary", body: `[]`, status: http.StatusMethodNotAllowed, problem: "method-not-allowed"},
+		{method: http.MethodGet, url: "/passengers/P2/location?at=noon", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{method: http.MethodGet, url: "/passengers/P1/location?at=2026-10-01T12:00:00Z", status: http.StatusNotFound, problem: "unknown-passenger"},
+		{method: http.MethodGet, url: "/passengers/P1", status: http.StatusNotFound, problem: "not-found"},
+		{method: http.MethodGet, url: "/passengers/P1/seats", status: http.StatusNotFound, problem: "not-found"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		// handle the request
+		pc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, tc.status, response.StatusCode, tc.url)
+		assert.True(t, strings.HasSuffix(problem.Type, "#"+tc.problem), tc.url)
+	}
+
+	// nothing was stored by the rejected appends
+	_, err = s.Legs("P1")
+	assert.True(t, errors.Is(err, store.ErrNotFound))
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// ProblemContentType is the content type of every error response, see RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// problem types are documented under the Errors section of the README
+const problemTypeBase = "https://github.com/SophisticaSean/flight_path_planner/blob/main/README.md#"
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// extension members
+
+	// InvalidLegs are the legs responsible for the problem, by their position in the request
+	InvalidLegs []InvalidLeg `json:"invalidLegs,omitempty"`
+	// Segments are the separate chains found when the legs don't connect
+	Segments []ProblemSegment `json:"segments,omitempty"`
+	// Gaps are where one segment ends without the next one starting there
+	Gaps []ProblemGap `json:"gaps,omitempty"`
+	// SuggestedLegs are the fewest legs that would join the segments into one path
+	SuggestedLegs [][]string `json:"suggestedLegs,omitempty"`
+	// Problems are every problem found when there's more than one
+	Problems []Problem `json:"problems,omitempty"`
+	// Line and Column point at where the request body stopped being valid JSON
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Offset is how many bytes into a streamed request body it stopped being valid JSON
+	Offset int64 `json:"offset,omitempty"`
+}
+
+// InvalidLeg is one offending leg of a request
+type InvalidLeg struct {
+	Index  int      `json:"index"`
+	Leg    []string `json:"leg"`
+	Reason string   `json:"reason"`
+}
+
+// ProblemSegment is one chain of legs that couldn't be connected to the others
+type ProblemSegment struct {
+	Departure string `json:"departure"`
+	Arrival   string `json:"arrival"`
+	Indexes   []int  `json:"indexes"`
+}
+
+// ProblemGap is a missing connection between two segments
+type ProblemGap struct {
+	Arrival   string `json:"arrival"`
+	Departure string `json:"departure"`
+	// ArrivingLegIndex and DepartingLegIndex are the request positions of the legs either side of the gap
+	ArrivingLegIndex  int    `json:"arrivingLegIndex"`
+	DepartingLegIndex int    `json:"departingLegIndex"`
+	Detail            string `json:"detail"`
+}
+
+// newProblem returns a Problem with its type derived from slug
+func newProblem(status int, slug, title, detail string) Problem {
+	return Problem{
+		Type:   problemTypeBase + slug,
+		Title:  title,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// writeProblem serializes p as the response
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	err := json.NewEncoder(w).Encode(p)
+	if err != nil {
+		panic("unable to write out JSON to client")
+	}
+}
+
+// invalidJSONProblem explains why body couldn't be unmarshalled,
+// including the line and column when encoding/json tells us the offset
+func invalidJSONProblem(body []byte, err error) Problem {
+	p := newProblem(http.StatusBadRequest, "invalid-json", "Request body is not valid JSON",
+		fmt.Sprintf(`Request body is not valid: %s. Valid input would be: '[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]'`, err))
+
+	offset := int64(-1)
+	syntaxErr := &json.SyntaxError{}
+	typeErr := &json.UnmarshalTypeError{}
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	} else if errors.As(err, &typeErr) {
+		offset = typeErr.Offset
+	}
+	if offset >= 0 && offset <= int64(len(body)) {
+		p.Line, p.Column = lineAndColumn(body, offset)
+	}
+	return p
+}
+
+// streamJSONProblem explains why a streamed request body couldn't be read,
+// streamed bodies aren't kept around so there's only a byte offset
+func streamJSONProblem(err *models.LegDecodeError) Problem {
+	p := newProblem(http.StatusBadRequest, "invalid-json", "Request body is not valid JSON",
+		fmt.Sprintf(`Request body is not valid: %s. Valid input would be: '[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]'`, err))
+	if err.Offset >= 0 {
+		p.Offset = err.Offset
+	}
+	return p
+}
+
+// lineAndColumn turns a byte offset into a 1 based line and column,
+// encoding/json offsets point just past the offending byte
+func lineAndColumn(body []byte, offset int64) (line, column int) {
+	prefix := body[:offset]
+	line = bytes.Count(prefix, []byte("\n")) + 1
+	column = len(prefix) - bytes.LastIndexByte(prefix, '\n') - 1
+	if column == 0 {
+		column = 1
+	}
+	return line, column
+}
+
+// solverProblem turns an error returned by a models.PathSolver into a Problem,
+// pointing at the offending legs of fi where the error says which they are
+func solverProblem(fi models.FlightsInput, err error) Problem {
+	validation := &models.ValidationError{}
+	malformed := &models.MalformedLegError{}
+	departure := &models.DuplicateDepartureError{}
+	arrival := &models.DuplicateArrivalError{}
+	disconnected := &models.DisconnectedSegmentsError{}
+	loop := &models.LoopError{}
+	unbalanced := &models.UnbalancedAirportsError{}
+	unknown := &models.UnknownAirportsError{}
+	empty := &models.EmptyInputError{}
+
+	switch {
+	// has to come first, errors.As matches the problems inside it too
+	case errors.As(err, &validation):
+		p := newProblem(http.StatusBadRequest, "validation-failed", "Flight plan has more than one problem", err.Error())
+		for _, problemErr := range validation.Errors {
+			problem := solverProblem(fi, problemErr)
+			p.InvalidLegs = append(p.InvalidLegs, problem.InvalidLegs...)
+			p.Problems = append(p.Problems, problem)
+		}
+		return p
+	case errors.As(err, &malformed):
+		p := newProblem(http.StatusBadRequest, "malformed-leg", "Malformed flight leg", err.Error())
+		p.InvalidLegs = invalidLegs(fi, []int{malformed.Index}, "leg does not have exactly two airports")
+		return p
+	case errors.As(err, &departure):
+		p := newProblem(http.StatusBadRequest, "duplicate-departure", "Duplicate departure airport", err.Error())
+		p.InvalidLegs = invalidLegs(fi, departure.Indexes, fmt.Sprintf("departs from %s", departure.Airport))
+		return p
+	case errors.As(err, &arrival):
+		p := newProblem(http.StatusBadRequest, "duplicate-arrival", "Duplicate arrival airport", err.Error())
+		p.InvalidLegs = invalidLegs(fi, arrival.Indexes, fmt.Sprintf("arrives at %s", arrival.Airport))
+		return p
+	case errors.As(err, &disconnected):
+		p := newProblem(http.StatusBadRequest, "disconnected-segments", "Flights do not form a single path", err.Error())
+		for _, segment := range disconnected.Segments {
+			p.Segments = append(p.Segments, ProblemSegment{
+				Departure: segment.Departure,
+				Arrival:   segment.Arrival,
+				Indexes:   segment.Indexes,
+			})
+		}
+		for _, gap := range disconnected.Gaps() {
+			p.Gaps = append(p.Gaps, ProblemGap{
+				Arrival:           gap.Arrival,
+				Departure:         gap.Departure,
+				ArrivingLegIndex:  gap.ArrivingLegIndex,
+				DepartingLegIndex: gap.DepartingLegIndex,
+				Detail:            gap.String(),
+			})
+		}
+		p.SuggestedLegs = disconnected.SuggestedLegs()
+		return p
+	case errors.As(err, &loop):
+		p := newProblem(http.StatusBadRequest, "loop", "Flight path contains a loop", err.Error())
+		p.InvalidLegs = invalidLegs(fi, loop.Indexes, "part of a loop")
+		return p
+	case errors.As(err, &unbalanced):
+		p := newProblem(http.StatusBadRequest, "unbalanced-airports", "Airports can't be visited in a single trip", err.Error())
+		p.InvalidLegs = invalidLegs(fi, unbalanced.Indexes, fmt.Sprintf("touches one of %v", unbalanced.Airports))
+		return p
+	case errors.As(err, &unknown):
+		p := newProblem(http.StatusBadRequest, "unknown-airport", "Unknown airport code", err.Error())
+		p.InvalidLegs = invalidLegs(fi, unknown.Indexes, fmt.Sprintf("uses one of %v", unknown.Airports))
+		return p
+	case errors.As(err, &empty):
+		return newProblem(http.StatusBadRequest, "empty-input", "No flights given", err.Error())
+	case errors.Is(err, models.ErrLoop):
+		// solvers that can't say which legs loop still wrap the sentinel
+		return newProblem(http.StatusBadRequest, "loop", "Flight path contains a loop", err.Error())
+	}
+
+	return newProblem(http.StatusBadRequest, "unsolvable", "Unable to calculate flight path", err.Error())
+}
+
+// invalidLegs looks up every index in fi and tags it with reason
+func invalidLegs(fi models.FlightsInput, indexes []int, reason string) (legs []InvalidLeg) {
+	for _, i := range indexes {
+		leg := InvalidLeg{Index: i, Reason: reason}
+		if i >= 0 && i < len(fi) {
+			leg.Leg = fi[i]
+		}
+		legs = append(legs, leg)
+	}
+	return legs
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// a /route request names the airports to route between with ?from=SFO&to=EWR
+// and can ask for ?by=stops instead of the default ?by=distance
+const (
+	FromQueryParam = "from"
+	ToQueryParam   = "to"
+	ByQueryParam   = "by"
+)
+
+// AvoidQueryParam lists airports a route mustn't go through, e.g. ?avoid=ORD,DEN,
+// and CarrierQueryParam the only carriers it can fly, e.g. ?carrier=DL
+const (
+	AvoidQueryParam   = "avoid"
+	CarrierQueryParam = "carrier"
+)
+
+// KQueryParam asks for the k best routes instead of only the best, e.g. ?k=5,
+// and MaxStopsQueryParam leaves out routes with more stops, e.g. ?maxStops=1
+const (
+	KQueryParam        = "k"
+	MaxStopsQueryParam = "maxStops"
+)
+
+// DefaultMaxK is the most routes a /route request can ask for
+const DefaultMaxK = 20
+
+// RouteResult is the response to /route, the best route in the same shape
+// /calculate answers with and the next best routes when more were asked for
+type RouteResult struct {
+	models.FlightOutput
+	// Alternatives are the next best routes, best first
+	Alternatives []models.FlightOutput `json:",omitempty"`
+}
+
+// RouteController serves the /route endpoint from the routes in Network
+type RouteController struct {
+	Network *graph.Network
+	// Airports has the coordinates routes are measured with
+	Airports *airports.DB
+	// MaxK is the most routes one request can ask for with KQueryParam
+	MaxK int
+}
+
+// NewRouteController returns a RouteController finding routes over network
+func NewRouteController(network *graph.Network, db *airports.DB) *RouteController {
+	return &RouteController{
+		Network:  network,
+		Airports: db,
+		MaxK:     DefaultMaxK,
+	}
+}
+
+// ServeHTTP lets a RouteController be mounted directly on a mux
+func (rc *RouteController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.Route(w, r)
+}
+
+// Route is the handler for the /route endpoint, it answers with the best
+// route in the same shape /calculate answers with, and the next best k-1
+// routes as Alternatives when asked for k of them
+func (rc *RouteController) Route(w http.ResponseWriter, r *http.Request) {
+	from, to, k, opts, problem := rc.routeRequest(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+
+	paths, err := rc.Network.Graph().KShortestPaths(from, to, k, opts)
+	if err != nil {
+		writeProblem(w, routeProblem(err))
+		return
+	}
+	result := RouteResult{FlightOutput: pathOutput(paths[0], rc.Airports)}
+	for _, path := range paths[1:] {
+		result.Alternatives = append(result.Alternatives, pathOutput(path, rc.Airports))
+	}
+	writeJSON(w, result)
+}
+
+// routeRequest reads the airports, how many routes to find and the constraints of a /route request
+func (rc *RouteController) routeRequest(r *http.Request) (from, to string, k int, opts graph.PathOptions, problem *Problem) {
+	invalidParameter := func(detail string) *Problem {
+		p := newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter", detail)
+		return &p
+	}
+	query := r.URL.Query()
+
+	from = models.NormalizeAirportCode(query.Get(FromQueryParam), rc.Airports)
+	to = models.NormalizeAirportCode(query.Get(ToQueryParam), rc.Airports)
+	if from == "" || to == "" {
+		return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameters %s and %s are both required, e.g. ?%s=SFO&%s=EWR.", FromQueryParam, ToQueryParam, FromQueryParam, ToQueryParam))
+	}
+	if from == to {
+		return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameters %s and %s are both %s.", FromQueryParam, ToQueryParam, from))
+	}
+
+	opts.Airports = rc.Airports
+	opts.By = query.Get(ByQueryParam)
+	if opts.By == "" {
+		opts.By = graph.ByDistance
+	}
+	if opts.By != graph.ByDistance && opts.By != graph.ByStops {
+		return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameter %s must be %s or %s, got %q.", ByQueryParam, graph.ByDistance, graph.ByStops, opts.By))
+	}
+
+	for _, code := range listQueryParam(r, AvoidQueryParam) {
+		airport := models.NormalizeAirportCode(code, rc.Airports)
+		if airport == from || airport == to {
+			return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameter %s can't include %s, the route starts or ends there.", AvoidQueryParam, airport))
+		}
+		opts.Avoid = append(opts.Avoid, airport)
+	}
+	opts.Carriers = listQueryParam(r, CarrierQueryParam)
+
+	k = 1
+	if value := query.Get(KQueryParam); value != "" {
+		var err error
+		k, err = strconv.Atoi(value)
+		if err != nil || k < 1 || (rc.MaxK > 0 && k > rc.MaxK) {
+			return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameter %s must be a whole number from 1 to %d, got %q.", KQueryParam, rc.MaxK, value))
+		}
+	}
+	if value := query.Get(MaxStopsQueryParam); value != "" {
+		maxStops, err := strconv.Atoi(value)
+		if err != nil || maxStops < 0 {
+			return from, to, k, opts, invalidParameter(fmt.Sprintf("Query parameter %s must be a whole number of at least 0, got %q.", MaxStopsQueryParam, value))
+		}
+		opts.MaxStops = &maxStops
+	}
+	return from, to, k, opts, nil
+}
+
+// listQueryParam splits a comma separated query parameter, repeating
+// the parameter works too: ?avoid=ORD&avoid=DEN is ?avoid=ORD,DEN
+func listQueryParam(r *http.Request, name string) (values []string) {
+	for _, value := range r.URL.Query()[name] {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// pathOutput turns a path into the FlightOutput /calculate would answer
+// with for its legs, with the carrier flying every leg
+func pathOutput(path graph.Path, db *airports.DB) models.FlightOutput {
+	legs := make(models.Legs, 0, len(path.Routes))
+	for _, route := range path.Routes {
+		legs = append(legs, models.Leg{From: route.From, To: route.To, Carrier: route.Carrier})
+	}
+	flightInput := legs.FlightsInput()
+
+	// a path never visits an airport twice, so this can't fail
+	flightOutput, _ := flightInput.FindStartAndEndFlightHashMap()
+	flightOutput.OrderedLegs = legs
+	trip, err := flightInput.Distance(flightOutput.LegOrder, db)
+	if err == nil {
+		flightOutput.Distance = &trip
+	}
+	return flightOutput
+}
+
+// routeProblem turns an error finding a route into a Problem
+func routeProblem(err error) Problem {
+	if errors.Is(err, graph.ErrNoRoute) {
+		return newProblem(http.StatusNotFound, "no-route", "No route found", err.Error())
+	}
+	return newProblem(http.StatusInternalServerError, "internal-error", "Internal server error", "Unable to find a route, please contact support.")
+}
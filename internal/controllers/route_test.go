@@ -0,0 +1,152 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/tj/assert"
+)
+
+// newRouteController serves routes from SFO to EWR through DEN, ORD or ATL
+func newRouteController(t *testing.T) *controllers.RouteController {
+	path := filepath.Join(t.TempDir(), "routes.csv")
+	schedule := "carrier,from,to\nUA,SFO,DEN\nUA,DEN,EWR\nAA,SFO,ORD\nAA,ORD,EWR\nDL,SFO,ATL\nDL,ATL,EWR\n"
+	err := os.WriteFile(path, []byte(schedule), 0o600)
+	assert.Nil(t, err)
+
+	network, err := graph.OpenNetwork(path, airports.Default())
+	assert.Nil(t, err)
+	return controllers.NewRouteController(network, airports.Default())
+}
+
+func TestRoute(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	rc := newRouteController(t)
+
+	for _, tc := range []struct {
+		url     string
+		path    string
+		carrier string
+	}{
+		{url: "/route?from=SFO&to=EWR", path: "SFO - ORD - EWR", carrier: "AA"},
+		{url: "/route?from=ksfo&to=EWR&avoid=ORD", path: "SFO - DEN - EWR", carrier: "UA"},
+		{url: "/route?from=SFO&to=EWR&avoid=ORD,DEN", path: "SFO - ATL - EWR", carrier: "DL"},
+		{url: "/route?from=SFO&to=EWR&carrier=DL", path: "SFO - ATL - EWR", carrier: "DL"},
+		{url: "/route?from=SFO&to=EWR&by=stops", path: "SFO - DEN - EWR", carrier: "UA"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		w := httptest.NewRecorder()
+
+		// handle the request
+		rc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		flightOutput := models.FlightOutput{}
+		err := json.NewDecoder(response.Body).Decode(&flightOutput)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusOK, response.StatusCode, tc.url)
+		assert.Equal(t, []string{"SFO", "EWR"}, flightOutput.CalculateResult, tc.url)
+		assert.Equal(t, tc.path, flightOutput.Path, tc.url)
+		assert.Equal(t, tc.carrier, flightOutput.OrderedLegs[0].Carrier, tc.url)
+		assert.Len(t, flightOutput.Distance.Legs, 2, tc.url)
+	}
+}
+
+func TestRouteAlternatives(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	rc := newRouteController(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/route?from=SFO&to=EWR&k=5&maxStops=1", nil)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	rc.ServeHTTP(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	result := controllers.RouteResult{}
+	err := json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	// the best route is the whole output and there are only two more
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "SFO - ORD - EWR", result.Path)
+	assert.Len(t, result.Alternatives, 2)
+	assert.Equal(t, "SFO - DEN - EWR", result.Alternatives[0].Path)
+	assert.Equal(t, "SFO - ATL - EWR", result.Alternatives[1].Path)
+	assert.Equal(t, []string{"SFO", "EWR"}, result.Alternatives[1].CalculateResult)
+	assert.Equal(t, "DL", result.Alternatives[1].OrderedLegs[0].Carrier)
+	assert.True(t, result.Distance.Total.Kilometers < result.Alternatives[0].Distance.Total.Kilometers)
+
+	// without k there's only the best route
+	req = httptest.NewRequest(http.MethodGet, "/route?from=SFO&to=EWR", nil)
+	w = httptest.NewRecorder()
+	rc.ServeHTTP(w, req)
+	assert.False(t, strings.Contains(w.Body.String(), "Alternatives"))
+}
+
+func TestRouteProblems(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	rc := newRouteController(t)
+
+	for _, tc := range []struct {
+		url     string
+		status  int
+		problem string
+	}{
+		{url: "/route?from=SFO", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=KSFO", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=EWR&by=time", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=EWR&avoid=EWR", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=EWR&k=0", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=EWR&k=21", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=SFO&to=EWR&maxStops=-1", status: http.StatusBadRequest, problem: "invalid-parameter"},
+		{url: "/route?from=EWR&to=SFO", status: http.StatusNotFound, problem: "no-route"},
+		{url: "/route?from=SFO&to=EWR&maxStops=0", status: http.StatusNotFound, problem: "no-route"},
+		{url: "/route?from=SFO&to=EWR&avoid=ORD&avoid=DEN&carrier=UA", status: http.StatusNotFound, problem: "no-route"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		w := httptest.NewRecorder()
+
+		// handle the request
+		rc.ServeHTTP(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, tc.status, response.StatusCode, tc.url)
+		assert.True(t, strings.HasSuffix(problem.Type, "#"+tc.problem), tc.url)
+	}
+}
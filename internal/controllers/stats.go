@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/stats"
+)
+
+// a stats request picks how far back to look with ?window=1h and how many
+// routes or airports to list with ?limit=20
+const (
+	WindowQueryParam = "window"
+	LimitQueryParam  = "limit"
+)
+
+// DefaultStatsLimit is how many routes or airports are listed without a ?limit=
+const DefaultStatsLimit = 10
+
+// StatsController serves /stats/routes and /stats/airports from Stats
+type StatsController struct {
+	Stats *stats.Aggregator
+	// DefaultLimit is used when a request doesn't give a limit
+	DefaultLimit int
+}
+
+// NewStatsController returns a StatsController serving what's been counted in s
+func NewStatsController(s *stats.Aggregator) *StatsController {
+	return &StatsController{
+		Stats:        s,
+		DefaultLimit: DefaultStatsLimit,
+	}
+}
+
+// Routes is the handler for the /stats/routes endpoint
+func (sc *StatsController) Routes(w http.ResponseWriter, r *http.Request) {
+	window, limit, problem := sc.windowAndLimit(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	writeJSON(w, sc.Stats.Routes(window, limit))
+}
+
+// Airports is the handler for the /stats/airports endpoint
+func (sc *StatsController) Airports(w http.ResponseWriter, r *http.Request) {
+	window, limit, problem := sc.windowAndLimit(r)
+	if problem != nil {
+		writeProblem(w, *problem)
+		return
+	}
+	writeJSON(w, sc.Stats.Airports(window, limit))
+}
+
+// windowAndLimit reads ?window= and ?limit=, a missing window is all of the
+// Aggregator's retention and a limit of 0 lists everything
+func (sc *StatsController) windowAndLimit(r *http.Request) (window time.Duration, limit int, problem *Problem) {
+	invalidParameter := func(detail string) *Problem {
+		p := newProblem(http.StatusBadRequest, "invalid-parameter", "Invalid query parameter", detail)
+		return &p
+	}
+
+	value := r.URL.Query().Get(WindowQueryParam)
+	if value != "" {
+		var err error
+		window, err = time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			return window, limit, invalidParameter(fmt.Sprintf("Query parameter %s must be a positive duration like 1h, got %q.", WindowQueryParam, value))
+		}
+		if window > sc.Stats.Retention {
+			return window, limit, invalidParameter(fmt.Sprintf("Query parameter %s can be at most %s, got %q.", WindowQueryParam, sc.Stats.Retention, value))
+		}
+	}
+
+	limit = sc.DefaultLimit
+	value = r.URL.Query().Get(LimitQueryParam)
+	if value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return window, limit, invalidParameter(fmt.Sprintf("Query parameter %s must be a whole number of at least 0, got %q.", LimitQueryParam, value))
+		}
+	}
+	return window, limit, nil
+}
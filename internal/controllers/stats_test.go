@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/stats"
+	"github.com/SophisticaSean/flight_path_calculator/internal/store"
+	"github.com/tj/assert"
+)
+
+func TestStats(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	cc := controllers.NewCalculateController(models.NewDefaultSolverRegistry(), models.HashMapSolver)
+	cc.Stats = stats.NewAggregator()
+	sc := controllers.NewStatsController(cc.Stats)
+
+	for _, tc := range []struct {
+		handler http.Handler
+		url     string
+		body    string
+	}{
+		{handler: cc, url: "/calculate", body: `[["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]`},
+		{handler: cc, url: "/calculate?stream=true", body: `[["ATL", "GSO"], ["SFO", "ATL"]]`},
+		{handler: controllers.NewBatchController(cc), url: "/calculate/batch", body: `{"itineraries": {"P1": [["SFO", "ATL"]], "P2": [["SFO", "ATL"], ["IND", "EWR"]]}}`},
+		// failed requests aren't counted
+		{handler: cc, url: "/calculate", body: `[["SFO", "ATL"], ["IND", "EWR"]]`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		tc.handler.ServeHTTP(w, req)
+	}
+
+	// stored legs were counted when they were solved, if ever, so viewing them again isn't
+	s := store.NewMemoryStore()
+	assert.Nil(t, s.Append("P1", models.Legs{{From: "JFK", To: "LHR"}}))
+	pc := controllers.NewPassengerController(s, cc)
+	pc.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/passengers/P1/itinerary", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/routes?limit=1", nil)
+	w := httptest.NewRecorder()
+
+	// handle the request
+	sc.Routes(w, req)
+
+	response := w.Result()
+	defer response.Body.Close()
+
+	routes := stats.RouteStats{}
+	err := json.NewDecoder(response.Body).Decode(&routes)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 3, routes.Itineraries)
+	assert.Equal(t, 7.0/3.0, routes.AveragePathLength)
+	assert.Equal(t, []stats.RouteCount{{From: "SFO", To: "ATL", Count: 1}}, routes.TopPairs)
+	assert.Equal(t, []stats.RouteCount{{From: "SFO", To: "ATL", Count: 3}}, routes.TopLegs)
+
+	req = httptest.NewRequest(http.MethodGet, "/stats/airports?window=1h", nil)
+	w = httptest.NewRecorder()
+
+	// handle the request
+	sc.Airports(w, req)
+
+	response = w.Result()
+	defer response.Body.Close()
+
+	airports := stats.AirportStats{}
+	err = json.NewDecoder(response.Body).Decode(&airports)
+	if err != nil {
+		t.Errorf("unable to unmarshal response body")
+	}
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "ATL", airports.Airports[0].Airport)
+	assert.Equal(t, 2, airports.Airports[0].Connections)
+	assert.Len(t, airports.Airports, 5)
+}
+
+func TestStatsProblems(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sc := controllers.NewStatsController(stats.NewAggregator())
+
+	for _, url := range []string{"/stats/routes?window=soon", "/stats/routes?window=-1h", "/stats/routes?window=48h", "/stats/routes?limit=ten", "/stats/routes?limit=-1"} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		// handle the request
+		sc.Routes(w, req)
+
+		response := w.Result()
+		defer response.Body.Close()
+
+		problem := controllers.Problem{}
+		err := json.NewDecoder(response.Body).Decode(&problem)
+		if err != nil {
+			t.Errorf("unable to unmarshal response body")
+		}
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, url)
+		assert.True(t, strings.HasSuffix(problem.Type, "#invalid-parameter"), url)
+	}
+}
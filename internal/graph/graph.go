@@ -0,0 +1,178 @@
+// Package graph is the directed network of scheduled routes between
+// airports, loaded from OpenFlights style routes.dat files or CSV schedules.
+package graph
+
+import (
+	"sort"
+)
+
+// Route is a scheduled service from one airport to another
+type Route struct {
+	// Carrier is the airline's IATA or ICAO code, e.g. UA
+	Carrier string
+	// From and To are normalized the way FlightsInput airports are, see models.NormalizeAirportCode
+	From string
+	To   string
+	// Stops is how many stops the service makes on the way, 0 for a direct flight
+	Stops int
+	// Equipment is the aircraft types flown, e.g. 738 and 320
+	Equipment []string `json:",omitempty"`
+	// Codeshare is set when the route is operated by another carrier
+	Codeshare bool `json:",omitempty"`
+}
+
+// SkippedRoute is a route that was left out of a Graph while loading
+type SkippedRoute struct {
+	// Line is the line of the file the route is on
+	Line   int
+	Route  Route
+	Reason string
+}
+
+// Graph is a directed network of routes, airports are its nodes
+type Graph struct {
+	routes []Route
+	// airport -> indexes of the routes leaving it, and of the routes landing there
+	out map[string][]int
+	in  map[string][]int
+	// skipped are the routes left out while loading
+	skipped []SkippedRoute
+}
+
+// New returns a Graph of routes
+func New(routes []Route) *Graph {
+	g := &Graph{
+		routes: routes,
+		out:    make(map[string][]int),
+		in:     make(map[string][]int),
+	}
+	for i, route := range routes {
+		g.out[route.From] = append(g.out[route.From], i)
+		g.in[route.To] = append(g.in[route.To], i)
+	}
+	return g
+}
+
+// Len returns how many routes are in g
+func (g *Graph) Len() int {
+	return len(g.routes)
+}
+
+// Routes returns every route in g in the order they were loaded
+func (g *Graph) Routes() []Route {
+	routes := make([]Route, len(g.routes))
+	copy(routes, g.routes)
+	return routes
+}
+
+// Skipped returns the routes left out of g while loading it,
+// usually because one of their airports isn't in the airport data
+func (g *Graph) Skipped() []SkippedRoute {
+	return g.skipped
+}
+
+// Airports returns every airport with a route to or from it, sorted
+func (g *Graph) Airports() []string {
+	airports := make([]string, 0, len(g.out)+len(g.in))
+	for airport := range g.out {
+		airports = append(airports, airport)
+	}
+	for airport := range g.in {
+		if _, ok := g.out[airport]; !ok {
+			airports = append(airports, airport)
+		}
+	}
+	sort.Strings(airports)
+	return airports
+}
+
+// From returns every route leaving airport
+func (g *Graph) From(airport string) []Route {
+	return g.lookup(g.out[airport])
+}
+
+// To returns every route landing at airport
+func (g *Graph) To(airport string) []Route {
+	return g.lookup(g.in[airport])
+}
+
+// Between returns every route from one airport to the other, any carrier
+func (g *Graph) Between(from, to string) []Route {
+	routes := []Route{}
+	for _, i := range g.out[from] {
+		if g.routes[i].To == to {
+			routes = append(routes, g.routes[i])
+		}
+	}
+	return routes
+}
+
+func (g *Graph) lookup(indexes []int) []Route {
+	routes := make([]Route, 0, len(indexes))
+	for _, i := range indexes {
+		routes = append(routes, g.routes[i])
+	}
+	return routes
+}
+
+// Destinations returns every airport with a route from airport, sorted
+func (g *Graph) Destinations(airport string) []string {
+	seen := make(map[string]bool)
+	destinations := []string{}
+	for _, i := range g.out[airport] {
+		to := g.routes[i].To
+		if !seen[to] {
+			seen[to] = true
+			destinations = append(destinations, to)
+		}
+	}
+	sort.Strings(destinations)
+	return destinations
+}
+
+// Reachable reports whether to can be flown to from from on any number of routes
+func (g *Graph) Reachable(from, to string) bool {
+	if from == to {
+		return true
+	}
+	found := false
+	g.walk(from, func(airport string) bool {
+		found = airport == to
+		return !found
+	})
+	return found
+}
+
+// ReachableFrom returns every airport that can be flown to from airport
+// on any number of routes, sorted, not counting airport itself
+func (g *Graph) ReachableFrom(airport string) []string {
+	reachable := []string{}
+	g.walk(airport, func(reached string) bool {
+		reachable = append(reachable, reached)
+		return true
+	})
+	sort.Strings(reachable)
+	return reachable
+}
+
+// walk visits every airport reachable from start breadth first, stopping
+// as soon as visit returns false
+func (g *Graph) walk(start string, visit func(airport string) bool) {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		airport := queue[0]
+		queue = queue[1:]
+		for _, i := range g.out[airport] {
+			to := g.routes[i].To
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+			if !visit(to) {
+				return
+			}
+			queue = append(queue, to)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphReachable(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g := graph.New([]graph.Route{
+		{Carrier: "UA", From: "SFO", To: "ORD"},
+		{Carrier: "AA", From: "SFO", To: "ORD"},
+		{Carrier: "UA", From: "ORD", To: "EWR"},
+		{Carrier: "BA", From: "EWR", To: "LHR"},
+		{Carrier: "DL", From: "ATL", To: "SFO"},
+		{Carrier: "AC", From: "YYZ", To: "YVR"},
+	})
+
+	assert.Equal(t, 6, g.Len())
+	assert.Equal(t, []string{"ATL", "EWR", "LHR", "ORD", "SFO", "YVR", "YYZ"}, g.Airports())
+	assert.Len(t, g.From("SFO"), 2)
+	assert.Len(t, g.To("SFO"), 1)
+	assert.Len(t, g.Between("SFO", "ORD"), 2)
+	assert.Empty(t, g.Between("ORD", "SFO"))
+	assert.Equal(t, []string{"ORD"}, g.Destinations("SFO"))
+
+	// routes are one way
+	assert.True(t, g.Reachable("ATL", "LHR"))
+	assert.False(t, g.Reachable("LHR", "ATL"))
+	assert.False(t, g.Reachable("SFO", "YVR"))
+	assert.True(t, g.Reachable("SFO", "SFO"))
+	assert.Equal(t, []string{"EWR", "LHR", "ORD", "SFO"}, g.ReachableFrom("ATL"))
+	assert.Empty(t, g.ReachableFrom("LHR"))
+	assert.Empty(t, g.ReachableFrom("nowhere"))
+}
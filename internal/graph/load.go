@@ -0,0 +1,250 @@
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// routes.dat columns
+const (
+	columnCarrier = iota
+	columnCarrierID
+	columnFrom
+	columnFromID
+	columnTo
+	columnToID
+	columnCodeshare
+	columnStops
+	columnEquipment
+	routesColumns
+)
+
+// routes.dat uses \N for a missing value
+const nullValue = `\N`
+
+// the header names a CSV schedule can use for each field, from and to are required
+var csvColumns = map[string][]string{
+	"carrier":   {"carrier", "airline"},
+	"from":      {"from", "source", "origin"},
+	"to":        {"to", "destination", "dest"},
+	"stops":     {"stops"},
+	"equipment": {"equipment", "aircraft"},
+	"codeshare": {"codeshare"},
+}
+
+// LoadOpenFlights reads an OpenFlights routes.dat, see LoadCSV for how db is used
+func LoadOpenFlights(r io.Reader, db *airports.DB) (*Graph, error) {
+	reader := newReader(r)
+	l := loader{db: db}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < routesColumns {
+			return nil, fmt.Errorf("routes line %d: expected %d columns, got %d", line, routesColumns, len(record))
+		}
+		for i, field := range record {
+			if field == nullValue {
+				record[i] = ""
+			}
+		}
+
+		route := Route{
+			Carrier:   record[columnCarrier],
+			From:      record[columnFrom],
+			To:        record[columnTo],
+			Codeshare: record[columnCodeshare] == "Y",
+			Equipment: parseEquipment(record[columnEquipment]),
+		}
+		route.Stops, err = parseStops(record[columnStops])
+		if err != nil {
+			return nil, fmt.Errorf("routes line %d: %w", line, err)
+		}
+		l.add(line, route)
+	}
+	return l.graph(), nil
+}
+
+// LoadCSV reads a CSV schedule with a header naming its columns, e.g.
+//
+//	carrier,from,to,stops,equipment
+//	UA,SFO,EWR,0,789 77W
+//
+// Only from and to are required, source and destination work as names too.
+// Airport codes are normalized like FlightsInput airports so "ksfo" is SFO.
+// When db isn't nil routes using an airport it doesn't know are left out
+// of the Graph and listed by Graph.Skipped instead.
+func LoadCSV(r io.Reader, db *airports.DB) (*Graph, error) {
+	reader := newReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return New(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns, err := csvHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	l := loader{db: db}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		route := Route{
+			Carrier:   field(record, "carrier"),
+			From:      field(record, "from"),
+			To:        field(record, "to"),
+			Equipment: parseEquipment(field(record, "equipment")),
+		}
+		codeshare := strings.ToUpper(field(record, "codeshare"))
+		route.Codeshare = codeshare == "Y" || codeshare == "TRUE"
+		route.Stops, err = parseStops(field(record, "stops"))
+		if err != nil {
+			return nil, fmt.Errorf("routes line %d: %w", line, err)
+		}
+		l.add(line, route)
+	}
+	return l.graph(), nil
+}
+
+// LoadFile reads a routes file from disk, a CSV schedule when its first line
+// is a header naming the from and to columns and a routes.dat otherwise
+func LoadFile(path string, db *airports.DB) (*Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header, err := newReader(f).Read()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	if _, headerErr := csvHeader(header); headerErr == nil {
+		return LoadCSV(f, db)
+	}
+	return LoadOpenFlights(f, db)
+}
+
+func newReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader
+}
+
+// csvHeader finds the column of every field a CSV schedule's header names
+func csvHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, names := range csvColumns {
+			for _, alias := range names {
+				if name == alias {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	_, from := columns["from"]
+	_, to := columns["to"]
+	if !from || !to {
+		return nil, fmt.Errorf("routes header %v needs a from and a to column", header)
+	}
+	return columns, nil
+}
+
+// parseEquipment splits "738 320" into its aircraft types, nil when there are none
+func parseEquipment(value string) []string {
+	equipment := strings.Fields(value)
+	if len(equipment) == 0 {
+		return nil
+	}
+	return equipment
+}
+
+func parseStops(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	stops, err := strconv.Atoi(value)
+	if err != nil || stops < 0 {
+		return 0, fmt.Errorf("invalid stops %q", value)
+	}
+	return stops, nil
+}
+
+// loader normalizes and checks routes as they're read
+type loader struct {
+	db      *airports.DB
+	routes  []Route
+	skipped []SkippedRoute
+}
+
+func (l *loader) add(line int, route Route) {
+	route.From = models.NormalizeAirportCode(route.From, l.db)
+	route.To = models.NormalizeAirportCode(route.To, l.db)
+	skip := func(reason string) {
+		l.skipped = append(l.skipped, SkippedRoute{Line: line, Route: route, Reason: reason})
+	}
+
+	switch {
+	case route.From == "" || route.To == "":
+		skip("missing an airport")
+	case route.From == route.To:
+		skip("departs from and arrives at the same airport")
+	case l.db != nil && !l.db.Known(route.From):
+		skip(l.unknownAirport(route.From))
+	case l.db != nil && !l.db.Known(route.To):
+		skip(l.unknownAirport(route.To))
+	default:
+		l.routes = append(l.routes, route)
+	}
+}
+
+// unknownAirport is why a route using code was skipped
+func (l *loader) unknownAirport(code string) string {
+	if l.db.Curated() {
+		return fmt.Sprintf("unknown airport %s, only the curated embedded airports are available", code)
+	}
+	return fmt.Sprintf("unknown airport %s", code)
+}
+
+func (l *loader) graph() *Graph {
+	g := New(l.routes)
+	g.skipped = l.skipped
+	return g
+}
@@ -0,0 +1,98 @@
+package graph_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+const routesDat = `UA,5209,SFO,3469,EWR,3494,,0,789 77W
+BA,1355,EWR,3494,LHR,507,Y,0,744
+AA,24,KJFK,3797,LAX,3484,,0,32B
+XX,\N,SFO,3469,QQQ,\N,,0,
+`
+
+func TestLoadOpenFlights(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g, err := graph.LoadOpenFlights(strings.NewReader(routesDat), airports.Default())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, g.Len())
+
+	routes := g.Routes()
+	assert.Equal(t, graph.Route{Carrier: "UA", From: "SFO", To: "EWR", Equipment: []string{"789", "77W"}}, routes[0])
+	assert.True(t, routes[1].Codeshare)
+	// ICAO codes join with the same nodes FlightsInput uses
+	assert.Equal(t, "JFK", routes[2].From)
+
+	// QQQ isn't in the airport data, which is only the curated airports
+	skipped := g.Skipped()
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, 4, skipped[0].Line)
+	assert.Equal(t, "unknown airport QQQ, only the curated embedded airports are available", skipped[0].Reason)
+
+	// without airport data nothing is skipped
+	g, err = graph.LoadOpenFlights(strings.NewReader(routesDat), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, g.Len())
+	assert.Equal(t, "KJFK", g.Routes()[2].From)
+
+	_, err = graph.LoadOpenFlights(strings.NewReader("UA,5209,SFO\n"), nil)
+	assert.EqualError(t, err, "routes line 1: expected 9 columns, got 3")
+	_, err = graph.LoadOpenFlights(strings.NewReader("UA,5209,SFO,3469,EWR,3494,,one,789\n"), nil)
+	assert.EqualError(t, err, `routes line 1: invalid stops "one"`)
+}
+
+func TestLoadCSV(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g, err := graph.LoadCSV(strings.NewReader("Origin, Destination, Airline, Stops\nsfo,ORD,UA,1\nORD,EWR,UA,\nORD,ORD,UA,0\n"), airports.Default())
+	assert.Nil(t, err)
+	assert.Equal(t, []graph.Route{
+		{Carrier: "UA", From: "SFO", To: "ORD", Stops: 1},
+		{Carrier: "UA", From: "ORD", To: "EWR"},
+	}, g.Routes())
+	assert.Len(t, g.Skipped(), 1)
+	assert.True(t, g.Reachable("SFO", "EWR"))
+
+	_, err = graph.LoadCSV(strings.NewReader("carrier,to\nUA,SFO\n"), nil)
+	assert.NotNil(t, err)
+}
+
+func TestNetworkReload(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "routes")
+	assert.Nil(t, os.WriteFile(path, []byte(routesDat), 0o600))
+
+	network, err := graph.OpenNetwork(path, airports.Default())
+	assert.Nil(t, err)
+	before := network.Graph()
+	assert.Equal(t, 3, before.Len())
+
+	// a CSV schedule is told apart from a routes.dat by its header
+	assert.Nil(t, os.WriteFile(path, []byte("from,to\nSFO,LAX\n"), 0o600))
+	assert.Nil(t, network.Reload())
+	assert.Equal(t, 1, network.Graph().Len())
+	// whoever already had the old graph still has all of it
+	assert.Equal(t, 3, before.Len())
+
+	// a broken file keeps the last good graph
+	assert.Nil(t, os.WriteFile(path, []byte("UA,SFO\n"), 0o600))
+	assert.NotNil(t, network.Reload())
+	assert.Equal(t, 1, network.Graph().Len())
+
+	_, err = graph.OpenNetwork(filepath.Join(t.TempDir(), "missing"), nil)
+	assert.NotNil(t, err)
+}
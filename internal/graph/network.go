@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"sync"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// Network is a Graph loaded from a file that can be reloaded while it's
+// being used, requests keep the Graph they started with until they're done
+type Network struct {
+	path string
+	db   *airports.DB
+
+	mu    sync.RWMutex
+	graph *Graph
+}
+
+// OpenNetwork loads the routes file at path with LoadFile, checking its airports against db
+func OpenNetwork(path string, db *airports.DB) (*Network, error) {
+	n := &Network{path: path, db: db}
+	err := n.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Graph returns the most recently loaded Graph
+func (n *Network) Graph() *Graph {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.graph
+}
+
+// Reload reads the routes file again, a file that fails to load
+// leaves the Graph that was already loaded in place
+func (n *Network) Reload() error {
+	g, err := LoadFile(n.path, n.db)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.graph = g
+	return nil
+}
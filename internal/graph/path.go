@@ -0,0 +1,492 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// what a shortest path can be shortest by
+const (
+	// ByDistance is the fewest great-circle kilometers, found with Dijkstra's algorithm
+	ByDistance = "distance"
+	// ByStops is the fewest legs, found with a breadth first search
+	ByStops = "stops"
+)
+
+// ErrNoRoute is returned when no routes join two airports, use errors.As
+// with *NoRouteError to find out which
+var ErrNoRoute = errors.New("no route between airports")
+
+// NoRouteError is returned when no routes join From to To within the constraints given
+type NoRouteError struct {
+	From string
+	To   string
+}
+
+func (e *NoRouteError) Error() string {
+	return fmt.Sprintf("No route from %s to %s.", e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrNoRoute) match
+func (e *NoRouteError) Is(target error) bool {
+	return target == ErrNoRoute
+}
+
+// PathOptions constrain the routes a path can use
+type PathOptions struct {
+	// By is ByDistance or ByStops, ByDistance when empty
+	By string
+	// Airports has the coordinates distances are worked out from, routes between
+	// airports it has no coordinates for can't be used ByDistance
+	Airports *airports.DB
+	// Avoid are airports the path mustn't go through
+	Avoid []string
+	// Carriers limits the path to routes flown by one of them, any carrier when empty
+	Carriers []string
+	// MaxStops is the most stops a path can make, see Path.Stops, no limit when nil
+	MaxStops *int
+}
+
+// Path is a way of getting from one airport to another over a Graph
+type Path struct {
+	// Airports are every airport on the way in order, including both ends
+	Airports []string
+	// Routes are the route taken for every leg
+	Routes []Route
+	// Kilometers is the great-circle distance of every leg added up, or 0
+	// when PathOptions.Airports doesn't have coordinates for all of them
+	Kilometers float64
+}
+
+// Stops is every airport the path changes planes at plus every stop its routes make on the way
+func (p Path) Stops() int {
+	stops := len(p.Routes) - 1
+	for _, route := range p.Routes {
+		stops += route.Stops
+	}
+	return stops
+}
+
+// key is the same for paths through the same airports in the same order,
+// which are the same path however many carriers fly it
+func (p Path) key() string {
+	return strings.Join(p.Airports, " ")
+}
+
+// ShortestPath finds the best path from one airport to another by opts.By,
+// or a *NoRouteError when there isn't one. Among routes between the same
+// two airports a nonstop one is taken before one with stops.
+func (g *Graph) ShortestPath(from, to string, opts PathOptions) (Path, error) {
+	paths, err := g.KShortestPaths(from, to, 1, opts)
+	if err != nil {
+		return Path{}, err
+	}
+	return paths[0], nil
+}
+
+// KShortestPaths finds up to k of the best loopless paths from one airport
+// to another with Yen's algorithm, best first by opts.By. Paths through the
+// same airports are only returned once, with the routes ShortestPath would
+// pick for them. With opts.MaxStops every path searched for stays within it,
+// so there are fewer than k only when fewer than k exist. It returns a
+// *NoRouteError when there isn't a single path.
+func (g *Graph) KShortestPaths(from, to string, k int, opts PathOptions) ([]Path, error) {
+	s, err := g.newSearch(opts)
+	if err != nil {
+		return nil, err
+	}
+	if k < 1 || s.avoid[from] || s.avoid[to] {
+		return nil, &NoRouteError{From: from, To: to}
+	}
+
+	first, ok := s.find(from, to, s.units(nil), nil, nil)
+	if !ok {
+		return nil, &NoRouteError{From: from, To: to}
+	}
+
+	// found is every path taken off candidates so far
+	found := []Path{first}
+	candidates := []Path{}
+	seen := map[string]bool{first.key(): true}
+	for len(found) < k {
+		latest := found[len(found)-1]
+
+		// branch off every airport of the latest path but the last
+		for i := 0; i < len(latest.Airports)-1; i++ {
+			spur := latest.Airports[i]
+			root := latest.Airports[:i+1]
+
+			// don't go back through the root, or leave it the way any path found already has
+			blockedAirports := make(map[string]bool, i)
+			for _, airport := range root[:i] {
+				blockedAirports[airport] = true
+			}
+			blockedLegs := make(map[[2]string]bool)
+			for _, path := range found {
+				if len(path.Airports) > i+1 && equalAirports(path.Airports[:i+1], root) {
+					blockedLegs[[2]string{path.Airports[i], path.Airports[i+1]}] = true
+				}
+			}
+
+			// the spur only gets the stops the root hasn't made
+			spurPath, ok := s.find(spur, to, s.units(latest.Routes[:i]), blockedAirports, blockedLegs)
+			if !ok {
+				continue
+			}
+			candidate := s.path(from, append(append([]Route{}, latest.Routes[:i]...), spurPath.Routes...))
+			if seen[candidate.key()] {
+				continue
+			}
+			seen[candidate.key()] = true
+			candidates = append(candidates, candidate)
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return s.less(candidates[a], candidates[b])
+		})
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+	return found, nil
+}
+
+func equalAirports(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// search is one set of PathOptions ready to find paths with
+type search struct {
+	g        *Graph
+	opts     PathOptions
+	avoid    map[string]bool
+	carriers map[string]bool
+}
+
+func (g *Graph) newSearch(opts PathOptions) (*search, error) {
+	if opts.By == "" {
+		opts.By = ByDistance
+	}
+	if opts.By != ByDistance && opts.By != ByStops {
+		return nil, fmt.Errorf("unknown path cost %q, expected %s or %s", opts.By, ByDistance, ByStops)
+	}
+	if opts.By == ByDistance && opts.Airports == nil {
+		return nil, errors.New("paths by distance need airport coordinates")
+	}
+
+	s := &search{
+		g:        g,
+		opts:     opts,
+		avoid:    make(map[string]bool, len(opts.Avoid)),
+		carriers: make(map[string]bool, len(opts.Carriers)),
+	}
+	for _, airport := range opts.Avoid {
+		s.avoid[airport] = true
+	}
+	for _, carrier := range opts.Carriers {
+		s.carriers[strings.ToUpper(carrier)] = true
+	}
+	return s, nil
+}
+
+// edge is the best usable route from an airport to a neighbor
+type edge struct {
+	route      int
+	kilometers float64
+}
+
+// edges works out the usable neighbors of an airport in the order their
+// first route was loaded, leaving out blocked airports and legs
+func (s *search) edges(airport string, blockedAirports map[string]bool, blockedLegs map[[2]string]bool) (neighbors []string, usable map[string]edge) {
+	usable = make(map[string]edge)
+	for _, i := range s.g.out[airport] {
+		route := s.g.routes[i]
+		if s.avoid[route.To] || blockedAirports[route.To] || blockedLegs[[2]string{route.From, route.To}] {
+			continue
+		}
+		if len(s.carriers) > 0 && !s.carriers[strings.ToUpper(route.Carrier)] {
+			continue
+		}
+		current, seen := usable[route.To]
+		if seen && s.g.routes[current.route].Stops <= route.Stops {
+			continue
+		}
+		e := edge{route: i}
+		if s.opts.By == ByDistance {
+			kilometers, ok := routeKilometers(s.opts.Airports, route)
+			if !ok {
+				continue
+			}
+			e.kilometers = kilometers
+		}
+		if !seen {
+			neighbors = append(neighbors, route.To)
+		}
+		usable[route.To] = e
+	}
+	return neighbors, usable
+}
+
+// routeUnits is how much of a MaxStops budget flying route uses, one for
+// the leg and one for every stop on the way, so a path's Stops is its
+// routes' units added up less one
+func routeUnits(route Route) int {
+	return 1 + route.Stops
+}
+
+// units is what's left of MaxStops for the rest of a path after flying
+// root, 0 without a limit
+func (s *search) units(root []Route) int {
+	if s.opts.MaxStops == nil {
+		return 0
+	}
+	units := *s.opts.MaxStops + 1
+	for _, route := range root {
+		units -= routeUnits(route)
+	}
+	return units
+}
+
+// find is the best path from one airport to another that doesn't use
+// blocked airports or legs. With MaxStops it's the best of the paths using
+// at most units, see search.units.
+func (s *search) find(from, to string, units int, blockedAirports map[string]bool, blockedLegs map[[2]string]bool) (Path, bool) {
+	if s.opts.MaxStops != nil {
+		routes, found := s.constrained(from, to, units, blockedAirports, blockedLegs)
+		if !found {
+			return Path{}, false
+		}
+		return s.path(from, routes), true
+	}
+
+	var previous map[string]edge
+	var found bool
+	if s.opts.By == ByDistance {
+		previous, found = s.dijkstra(from, to, blockedAirports, blockedLegs)
+	} else {
+		previous, found = s.breadthFirst(from, to, blockedAirports, blockedLegs)
+	}
+	if !found {
+		return Path{}, false
+	}
+
+	// walk back from to
+	routes := []Route{}
+	for airport := to; airport != from; {
+		route := s.g.routes[previous[airport].route]
+		routes = append(routes, route)
+		airport = route.From
+	}
+	for i, j := 0, len(routes)-1; i < j; i, j = i+1, j-1 {
+		routes[i], routes[j] = routes[j], routes[i]
+	}
+	return s.path(from, routes), true
+}
+
+// path puts routes flown in order from an airport together into a Path
+func (s *search) path(from string, routes []Route) Path {
+	path := Path{Airports: []string{from}, Routes: routes}
+	known := true
+	for _, route := range routes {
+		path.Airports = append(path.Airports, route.To)
+		kilometers, ok := routeKilometers(s.opts.Airports, route)
+		path.Kilometers += kilometers
+		known = known && ok
+	}
+	if !known {
+		path.Kilometers = 0
+	}
+	return path
+}
+
+// less ranks paths by opts.By, then by stops and kilometers, then by their airports
+func (s *search) less(a, b Path) bool {
+	if s.opts.By == ByStops && len(a.Routes) != len(b.Routes) {
+		return len(a.Routes) < len(b.Routes)
+	}
+	if a.Kilometers != b.Kilometers {
+		return a.Kilometers < b.Kilometers
+	}
+	if a.Stops() != b.Stops() {
+		return a.Stops() < b.Stops()
+	}
+	return a.key() < b.key()
+}
+
+// routeKilometers is the great-circle distance flown by route
+func routeKilometers(db *airports.DB, route Route) (float64, bool) {
+	if db == nil {
+		return 0, false
+	}
+	from, ok := db.Lookup(route.From)
+	if !ok {
+		return 0, false
+	}
+	to, ok := db.Lookup(route.To)
+	if !ok {
+		return 0, false
+	}
+	return airports.GreatCircleKilometers(from, to), true
+}
+
+// breadthFirst finds the fewest legs from one airport to another, previous
+// is the edge every airport on the way was reached by
+func (s *search) breadthFirst(from, to string, blockedAirports map[string]bool, blockedLegs map[[2]string]bool) (previous map[string]edge, found bool) {
+	previous = make(map[string]edge)
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		airport := queue[0]
+		queue = queue[1:]
+		if airport == to {
+			return previous, true
+		}
+		neighbors, usable := s.edges(airport, blockedAirports, blockedLegs)
+		for _, neighbor := range neighbors {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+			previous[neighbor] = usable[neighbor]
+			queue = append(queue, neighbor)
+		}
+	}
+	return previous, false
+}
+
+// dijkstra finds the fewest kilometers from one airport to another,
+// previous is the edge every airport on the way was reached by
+func (s *search) dijkstra(from, to string, blockedAirports map[string]bool, blockedLegs map[[2]string]bool) (previous map[string]edge, found bool) {
+	previous = make(map[string]edge)
+	distances := map[string]float64{from: 0}
+	done := make(map[string]bool)
+	queue := &airportQueue{{airport: from}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queued)
+		if done[current.airport] {
+			continue
+		}
+		done[current.airport] = true
+		if current.airport == to {
+			return previous, true
+		}
+
+		neighbors, usable := s.edges(current.airport, blockedAirports, blockedLegs)
+		for _, neighbor := range neighbors {
+			e := usable[neighbor]
+			distance := current.kilometers + e.kilometers
+			known, ok := distances[neighbor]
+			if done[neighbor] || (ok && known <= distance) {
+				continue
+			}
+			distances[neighbor] = distance
+			previous[neighbor] = e
+			heap.Push(queue, queued{airport: neighbor, kilometers: distance})
+		}
+	}
+	return previous, false
+}
+
+// state is an airport reached having used units of a MaxStops budget
+type state struct {
+	airport string
+	units   int
+}
+
+// constrained is dijkstra over every airport paired with the units used
+// reaching it, so a path that's longer but makes fewer stops isn't thrown
+// away for a shorter one that can't finish within units. ByStops every leg
+// costs the same. It returns the routes of the best path found.
+func (s *search) constrained(from, to string, units int, blockedAirports map[string]bool, blockedLegs map[[2]string]bool) (routes []Route, found bool) {
+	start := state{airport: from}
+	previous := make(map[state]edge)
+	costs := map[state]float64{start: 0}
+	done := make(map[state]bool)
+	queue := &airportQueue{{airport: from}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queued)
+		at := state{airport: current.airport, units: current.units}
+		if done[at] {
+			continue
+		}
+		done[at] = true
+		if at.airport == to {
+			// walk back from to, every edge says how many units it used
+			for at != start {
+				route := s.g.routes[previous[at].route]
+				routes = append(routes, route)
+				at = state{airport: route.From, units: at.units - routeUnits(route)}
+			}
+			for i, j := 0, len(routes)-1; i < j; i, j = i+1, j-1 {
+				routes[i], routes[j] = routes[j], routes[i]
+			}
+			return routes, true
+		}
+
+		neighbors, usable := s.edges(at.airport, blockedAirports, blockedLegs)
+		for _, neighbor := range neighbors {
+			e := usable[neighbor]
+			next := state{airport: neighbor, units: at.units + routeUnits(s.g.routes[e.route])}
+			if next.units > units {
+				continue
+			}
+			cost := current.kilometers + e.kilometers
+			if s.opts.By == ByStops {
+				cost = current.kilometers + 1
+			}
+			known, ok := costs[next]
+			if done[next] || (ok && known <= cost) {
+				continue
+			}
+			costs[next] = cost
+			previous[next] = e
+			heap.Push(queue, queued{airport: neighbor, kilometers: cost, units: next.units})
+		}
+	}
+	return nil, false
+}
+
+// queued is an airport waiting in an airportQueue, units is only used by
+// constrained and kilometers is legs flown there ByStops
+type queued struct {
+	airport    string
+	kilometers float64
+	units      int
+}
+
+// airportQueue is a container/heap of airports, closest first
+type airportQueue []queued
+
+func (q airportQueue) Len() int { return len(q) }
+func (q airportQueue) Less(i, j int) bool {
+	if q[i].kilometers != q[j].kilometers {
+		return q[i].kilometers < q[j].kilometers
+	}
+	if q[i].airport != q[j].airport {
+		return q[i].airport < q[j].airport
+	}
+	return q[i].units < q[j].units
+}
+func (q airportQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *airportQueue) Push(x interface{}) { *q = append(*q, x.(queued)) }
+func (q *airportQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
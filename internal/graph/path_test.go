@@ -0,0 +1,188 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNetwork has a two leg way from SFO to EWR through DEN, a three leg way
+// through SEA and ORD that's shorter, and a nonstop that only DL flies
+func testNetwork() *graph.Graph {
+	return graph.New([]graph.Route{
+		{Carrier: "UA", From: "SFO", To: "DEN"},
+		{Carrier: "UA", From: "DEN", To: "EWR"},
+		{Carrier: "AS", From: "SFO", To: "SEA"},
+		{Carrier: "AA", From: "SEA", To: "ORD"},
+		{Carrier: "AA", From: "ORD", To: "EWR"},
+		{Carrier: "UA", From: "SFO", To: "ORD", Stops: 1},
+		{Carrier: "AA", From: "SFO", To: "ORD"},
+		{Carrier: "DL", From: "SFO", To: "ATL"},
+		{Carrier: "DL", From: "ATL", To: "EWR"},
+	})
+}
+
+func TestShortestPath(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g := testNetwork()
+	db := airports.Default()
+
+	// SFO - ORD - EWR is the shortest, flown nonstop by AA rather than UA with a stop
+	path, err := g.ShortestPath("SFO", "EWR", graph.PathOptions{Airports: db})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "ORD", "EWR"}, path.Airports)
+	assert.Equal(t, "AA", path.Routes[0].Carrier)
+	assert.Equal(t, 0, path.Routes[0].Stops)
+	assert.InDelta(t, 2966+1155, path.Kilometers, 20)
+
+	path, err = g.ShortestPath("SFO", "EWR", graph.PathOptions{Airports: db, Avoid: []string{"ORD"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "DEN", "EWR"}, path.Airports)
+
+	path, err = g.ShortestPath("SFO", "EWR", graph.PathOptions{Airports: db, Carriers: []string{"dl"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "ATL", "EWR"}, path.Airports)
+
+	// fewest stops doesn't need coordinates, the first route in the file wins a tie
+	path, err = g.ShortestPath("SFO", "EWR", graph.PathOptions{By: graph.ByStops})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "DEN", "EWR"}, path.Airports)
+	assert.Equal(t, 0.0, path.Kilometers)
+
+	path, err = g.ShortestPath("SFO", "EWR", graph.PathOptions{By: graph.ByStops, Carriers: []string{"AS", "AA"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "ORD", "EWR"}, path.Airports)
+}
+
+func TestShortestPathNoRoute(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g := testNetwork()
+	db := airports.Default()
+
+	for _, tc := range []struct {
+		from, to string
+		opts     graph.PathOptions
+	}{
+		{from: "EWR", to: "SFO", opts: graph.PathOptions{Airports: db}},
+		{from: "SFO", to: "EWR", opts: graph.PathOptions{Airports: db, Avoid: []string{"ORD", "DEN", "ATL"}}},
+		{from: "SFO", to: "EWR", opts: graph.PathOptions{By: graph.ByStops, Carriers: []string{"AS"}}},
+		{from: "SFO", to: "EWR", opts: graph.PathOptions{By: graph.ByStops, Avoid: []string{"EWR"}}},
+		{from: "SFO", to: "LHR", opts: graph.PathOptions{By: graph.ByStops}},
+	} {
+		_, err := g.ShortestPath(tc.from, tc.to, tc.opts)
+		assert.True(t, errors.Is(err, graph.ErrNoRoute), tc)
+		noRoute := &graph.NoRouteError{}
+		assert.True(t, errors.As(err, &noRoute))
+		assert.Equal(t, tc.from, noRoute.From)
+	}
+
+	_, err := g.ShortestPath("SFO", "EWR", graph.PathOptions{By: "time"})
+	assert.NotNil(t, err)
+	_, err = g.ShortestPath("SFO", "EWR", graph.PathOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestKShortestPaths(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	g := testNetwork()
+	db := airports.Default()
+	airportsOf := func(paths []graph.Path) [][]string {
+		all := [][]string{}
+		for _, path := range paths {
+			all = append(all, path.Airports)
+		}
+		return all
+	}
+
+	// every way there shortest first, UA's SFO - ORD with a stop is the same path as AA's nonstop
+	paths, err := g.KShortestPaths("SFO", "EWR", 10, graph.PathOptions{Airports: db})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"SFO", "ORD", "EWR"},
+		{"SFO", "DEN", "EWR"},
+		{"SFO", "ATL", "EWR"},
+		{"SFO", "SEA", "ORD", "EWR"},
+	}, airportsOf(paths))
+	assert.Equal(t, "AA", paths[0].Routes[0].Carrier)
+	for i := 1; i < len(paths); i++ {
+		assert.True(t, paths[i-1].Kilometers <= paths[i].Kilometers)
+	}
+
+	paths, err = g.KShortestPaths("SFO", "EWR", 2, graph.PathOptions{Airports: db})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"SFO", "ORD", "EWR"}, {"SFO", "DEN", "EWR"}}, airportsOf(paths))
+
+	// fewest legs first, like ShortestPath ties go to the routes first in the file
+	paths, err = g.KShortestPaths("SFO", "EWR", 10, graph.PathOptions{By: graph.ByStops})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"SFO", "DEN", "EWR"},
+		{"SFO", "ORD", "EWR"},
+		{"SFO", "ATL", "EWR"},
+		{"SFO", "SEA", "ORD", "EWR"},
+	}, airportsOf(paths))
+	assert.Equal(t, 2, paths[3].Stops())
+
+	oneStop := 1
+	paths, err = g.KShortestPaths("SFO", "EWR", 10, graph.PathOptions{Airports: db, MaxStops: &oneStop})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(paths))
+
+	nonstop := 0
+	_, err = g.KShortestPaths("SFO", "EWR", 10, graph.PathOptions{Airports: db, MaxStops: &nonstop})
+	assert.True(t, errors.Is(err, graph.ErrNoRoute))
+	_, err = g.KShortestPaths("SFO", "EWR", 0, graph.PathOptions{Airports: db})
+	assert.True(t, errors.Is(err, graph.ErrNoRoute))
+}
+
+func TestKShortestPathsMaxStopsDuringSearch(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// every way through the lower 48 stops on both legs, and the only way
+	// within one stop is the long way round through Anchorage
+	routes := []graph.Route{
+		{Carrier: "AS", From: "SFO", To: "ANC"},
+		{Carrier: "AS", From: "ANC", To: "EWR"},
+	}
+	for _, airport := range []string{"ATL", "AUS", "BNA", "BOS", "BWI", "CLT", "DCA", "DEN", "DFW", "DTW", "GSO", "IAD", "IAH", "IND", "JFK", "LAS", "LAX", "LGA", "MCO", "MDW", "MIA", "MSP", "ORD", "PDX", "PHL", "PHX", "SAN", "SEA", "SLC", "STL", "TPA", "RDU", "OAK"} {
+		routes = append(routes,
+			graph.Route{Carrier: "UA", From: "SFO", To: airport, Stops: 1},
+			graph.Route{Carrier: "UA", From: airport, To: "EWR", Stops: 1},
+		)
+	}
+	g := graph.New(routes)
+	db := airports.Default()
+
+	// far more paths are shorter than the one within the limit
+	oneStop := 1
+	path, err := g.ShortestPath("SFO", "EWR", graph.PathOptions{Airports: db, MaxStops: &oneStop})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "ANC", "EWR"}, path.Airports)
+
+	paths, err := g.KShortestPaths("SFO", "EWR", 5, graph.PathOptions{Airports: db, MaxStops: &oneStop})
+	assert.Nil(t, err)
+	assert.Len(t, paths, 1)
+
+	// with room for the stops every way there is found
+	threeStops := 3
+	paths, err = g.KShortestPaths("SFO", "EWR", 50, graph.PathOptions{Airports: db, MaxStops: &threeStops})
+	assert.Nil(t, err)
+	assert.Len(t, paths, 34)
+	for _, path := range paths {
+		assert.True(t, path.Stops() <= threeStops)
+	}
+}
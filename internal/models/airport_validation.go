@@ -0,0 +1,36 @@
+package models
+
+import "github.com/SophisticaSean/flight_path_calculator/internal/airports"
+
+// ValidateAirports returns an *UnknownAirportsError naming every code
+// in fi that isn't an IATA or ICAO code in db, every code when db is nil.
+// Malformed flights are left for the solvers to report.
+func (fi FlightsInput) ValidateAirports(db *airports.DB) error {
+	unknown := &UnknownAirportsError{Curated: db.Curated()}
+	seen := make(map[string]bool)
+	for i, flightPair := range fi {
+		if len(flightPair) != 2 {
+			continue
+		}
+
+		blamed := false
+		for _, code := range flightPair {
+			if db.Known(code) {
+				continue
+			}
+			blamed = true
+			if !seen[code] {
+				seen[code] = true
+				unknown.Airports = append(unknown.Airports, code)
+			}
+		}
+		if blamed {
+			unknown.Indexes = append(unknown.Indexes, i)
+		}
+	}
+
+	if len(unknown.Airports) > 0 {
+		return unknown
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAirports(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// IATA and ICAO codes are both fine
+	input := models.FlightsInput{{"IND", "KEWR"}, {"EWR", "SFO"}}
+	assert.Nil(t, input.ValidateAirports(airports.Default()))
+
+	// every unknown code is reported once, with every leg using it
+	input = models.FlightsInput{{"hello", "world"}, {"IND", "EWR"}, {"EWR", "hello"}, {"SFO"}}
+	err := input.ValidateAirports(airports.Default())
+	assert.True(t, errors.Is(err, models.ErrUnknownAirport))
+
+	unknown := &models.UnknownAirportsError{}
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"hello", "world"}, unknown.Airports)
+	assert.Equal(t, []int{0, 2}, unknown.Indexes)
+	// the embedded airports aren't every airport, so the error says so
+	assert.True(t, unknown.Curated)
+	assert.Contains(t, err.Error(), "curated")
+
+	// a loaded airports.dat is taken to be complete
+	db := airports.NewDB([]airports.Airport{{IATA: "IND"}, {IATA: "EWR"}})
+	err = input.ValidateAirports(db)
+	assert.True(t, errors.As(err, &unknown))
+	assert.False(t, unknown.Curated)
+	assert.NotContains(t, err.Error(), "curated")
+
+	// without airports every code is unknown
+	err = models.FlightsInput{{"IND", "EWR"}}.ValidateAirports(nil)
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"IND", "EWR"}, unknown.Airports)
+}
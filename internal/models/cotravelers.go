@@ -0,0 +1,133 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// kinds of SharedLeg match
+const (
+	// both legs have the same flight number
+	SameFlight = "flight"
+	// both legs fly the same route on the same day
+	SameRoute = "route"
+)
+
+// SharedLeg is a leg two passengers both flew
+type SharedLeg struct {
+	// LegIndex and OtherLegIndex are the positions of the leg in each passenger's legs
+	LegIndex      int
+	OtherLegIndex int
+	From          string
+	To            string
+	Flight        string `json:",omitempty"`
+	// Date is the day the leg departed at its departure airport, when it has a departure time
+	Date  string `json:",omitempty"`
+	Match string
+}
+
+// CoTraveler is another passenger who flew some of the same legs
+type CoTraveler struct {
+	Passenger string
+	// Score is the shared legs over every distinct leg the two passengers
+	// flew between them, 1 when they flew exactly the same legs
+	Score      float64
+	SharedLegs []SharedLeg
+}
+
+// departureDates returns the day each leg of ls departs on at its departure
+// airport, "" for legs without a departure time. Airports db doesn't know, or
+// that have no time zone, keep the offset the departure was given with.
+func (ls Legs) departureDates(db *airports.DB) []string {
+	dates := make([]string, len(ls))
+	location := airportLocations(db)
+	for i, leg := range ls {
+		if leg.Departure == nil {
+			continue
+		}
+		departure := *leg.Departure
+		if loc := location(leg.From); loc != nil {
+			departure = departure.In(loc)
+		}
+		dates[i] = departure.Format("2006-01-02")
+	}
+	return dates
+}
+
+// flightNumber makes "dl 123" and "DL123" the same flight
+func flightNumber(leg Leg) string {
+	return strings.ToUpper(strings.ReplaceAll(leg.Flight, " ", ""))
+}
+
+// sharedLeg reports whether a, departing on dateA, and b, departing on
+// dateB, are the same flight. Flight numbers repeat every day, so both legs
+// need a departure time on the same day for either kind of match.
+func sharedLeg(a, b Leg, dateA, dateB string) (match string, ok bool) {
+	sameDay := dateA != "" && dateA == dateB
+	if sameDay && a.Flight != "" && flightNumber(a) == flightNumber(b) {
+		return SameFlight, true
+	}
+	if sameDay && a.From == b.From && a.To == b.To {
+		return SameRoute, true
+	}
+	return "", false
+}
+
+// SharedLegs returns every leg of ls that other flew too, in the order of
+// ls. Each of other's legs is only matched once. Days are taken in the time
+// zone of each leg's departure airport in db, which can be nil to take them
+// as the departures were given.
+func (ls Legs) SharedLegs(other Legs, db *airports.DB) (shared []SharedLeg) {
+	dates, otherDates := ls.departureDates(db), other.departureDates(db)
+	matched := make([]bool, len(other))
+	for i, leg := range ls {
+		for j, otherLeg := range other {
+			if matched[j] {
+				continue
+			}
+			match, ok := sharedLeg(leg, otherLeg, dates[i], otherDates[j])
+			if !ok {
+				continue
+			}
+			matched[j] = true
+			shared = append(shared, SharedLeg{
+				LegIndex:      i,
+				OtherLegIndex: j,
+				From:          leg.From,
+				To:            leg.To,
+				Flight:        leg.Flight,
+				Date:          dates[i],
+				Match:         match,
+			})
+			break
+		}
+	}
+	return shared
+}
+
+// FindCoTravelers compares legs with every other passenger's legs and
+// returns the ones sharing at least one leg, best Score first. db places
+// departures in their airport's time zone, as SharedLegs does.
+func FindCoTravelers(legs Legs, others map[string]Legs, db *airports.DB) []CoTraveler {
+	coTravelers := []CoTraveler{}
+	for passenger, otherLegs := range others {
+		shared := legs.SharedLegs(otherLegs, db)
+		if len(shared) == 0 {
+			continue
+		}
+		coTravelers = append(coTravelers, CoTraveler{
+			Passenger:  passenger,
+			Score:      float64(len(shared)) / float64(len(legs)+len(otherLegs)-len(shared)),
+			SharedLegs: shared,
+		})
+	}
+	sort.Slice(coTravelers, func(a, b int) bool {
+		if coTravelers[a].Score != coTravelers[b].Score {
+			return coTravelers[a].Score > coTravelers[b].Score
+		}
+		return coTravelers[a].Passenger < coTravelers[b].Passenger
+	})
+	return coTravelers
+}
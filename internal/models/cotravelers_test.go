@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindCoTravelers(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	parse := func(body string) models.Legs {
+		legs := models.Legs{}
+		err := json.Unmarshal([]byte(body), &legs)
+		assert.Nil(t, err)
+		return legs
+	}
+
+	legs := parse(`[
+  {"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00:00-07:00", "flight": "DL 123"},
+  {"from": "ATL", "to": "GSO", "departure": "2026-10-01T18:00:00-04:00"},
+  ["GSO", "IND"]
+  ]`)
+	others := map[string]models.Legs{
+		// same flight number written differently, and the same route that day
+		"P2": parse(`[
+  {"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00:00-07:00", "flight": "dl123"},
+  {"from": "ATL", "to": "GSO", "departure": "2026-10-01T09:00:00-04:00"}
+  ]`),
+		// same flight number a day later isn't the same flight
+		"P3": parse(`[{"from": "SFO", "to": "ATL", "departure": "2026-10-02T08:00:00-07:00", "flight": "DL123"}]`),
+		// without a time there's no telling which day's DL123 it was
+		"P4": parse(`[{"from": "SFO", "to": "ATL", "flight": "DL123"}, ["GSO", "IND"], ["IND", "EWR"]]`),
+		// the same route given in UTC, 22:00 is still the 1st in Atlanta, and a
+		// timed leg never matches one without a time
+		"P5": parse(`[{"from": "ATL", "to": "GSO", "departure": "2026-10-01T22:00:00Z"}, {"from": "GSO", "to": "IND", "departure": "2026-10-02T06:30:00Z"}]`),
+	}
+
+	coTravelers := models.FindCoTravelers(legs, others, airports.Default())
+	assert.Len(t, coTravelers, 2)
+
+	assert.Equal(t, "P2", coTravelers[0].Passenger)
+	assert.InDelta(t, 2.0/3.0, coTravelers[0].Score, 0.0001)
+	assert.Equal(t, []models.SharedLeg{
+		{LegIndex: 0, OtherLegIndex: 0, From: "SFO", To: "ATL", Flight: "DL 123", Date: "2026-10-01", Match: models.SameFlight},
+		{LegIndex: 1, OtherLegIndex: 1, From: "ATL", To: "GSO", Date: "2026-10-01", Match: models.SameRoute},
+	}, coTravelers[0].SharedLegs)
+
+	assert.Equal(t, "P5", coTravelers[1].Passenger)
+	assert.InDelta(t, 0.25, coTravelers[1].Score, 0.0001)
+	assert.Equal(t, []models.SharedLeg{
+		{LegIndex: 1, OtherLegIndex: 0, From: "ATL", To: "GSO", Date: "2026-10-01", Match: models.SameRoute},
+	}, coTravelers[1].SharedLegs)
+
+	assert.Empty(t, models.FindCoTravelers(legs, nil, airports.Default()))
+}
+
+func TestFindCoTravelersAirportDay(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	parse := func(body string) models.Legs {
+		legs := models.Legs{}
+		err := json.Unmarshal([]byte(body), &legs)
+		assert.Nil(t, err)
+		return legs
+	}
+
+	// the same late flight out of SFO, given in Pacific time and in UTC
+	legs := parse(`[{"from": "SFO", "to": "JFK", "departure": "2026-10-01T23:30:00-07:00", "flight": "UA 1"}]`)
+	others := map[string]models.Legs{
+		"P2": parse(`[{"from": "SFO", "to": "JFK", "departure": "2026-10-02T06:30:00Z", "flight": "UA1"}]`),
+	}
+
+	coTravelers := models.FindCoTravelers(legs, others, airports.Default())
+	assert.Len(t, coTravelers, 1)
+	assert.Equal(t, []models.SharedLeg{
+		{LegIndex: 0, OtherLegIndex: 0, From: "SFO", To: "JFK", Flight: "UA 1", Date: "2026-10-01", Match: models.SameFlight},
+	}, coTravelers[0].SharedLegs)
+
+	// without airports the days are the ones each time was given in, which differ
+	assert.Empty(t, models.FindCoTravelers(legs, others, nil))
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// LegDecoder reads a JSON list of legs one leg at a time with
+// json.Decoder.Token, so a list too big to comfortably hold in memory
+// never has to be read in whole
+type LegDecoder struct {
+	dec     *json.Decoder
+	started bool
+	done    bool
+	// index is the position of the next leg in the list
+	index int
+}
+
+// NewLegDecoder returns a LegDecoder reading from r
+func NewLegDecoder(r io.Reader) *LegDecoder {
+	return &LegDecoder{dec: json.NewDecoder(r)}
+}
+
+// LegDecodeError is a leg in a list that couldn't be read
+type LegDecodeError struct {
+	// Index is the position of the leg in the list
+	Index int
+	// Offset is how many bytes into the input reading failed, -1 if unknown
+	Offset int64
+	Err    error
+}
+
+func (e *LegDecodeError) Error() string {
+	return fmt.Sprintf("leg %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying JSON error
+func (e *LegDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Next returns the next leg in the list, or io.EOF once the whole list
+// has been read. A null list has no legs. Anything else that isn't a
+// list of legs, or anything but whitespace after it, is a *LegDecodeError.
+func (ld *LegDecoder) Next() (Leg, error) {
+	if ld.done {
+		return Leg{}, io.EOF
+	}
+
+	if !ld.started {
+		ld.started = true
+		token, err := ld.dec.Token()
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return Leg{}, ld.decodeError(err)
+		}
+		if token == nil {
+			return Leg{}, ld.end()
+		}
+		if token != json.Delim('[') {
+			return Leg{}, ld.decodeError(&json.UnmarshalTypeError{Value: jsonKind(token), Type: reflect.TypeOf(Legs{}), Offset: ld.dec.InputOffset()})
+		}
+	}
+
+	if !ld.dec.More() {
+		// the closing ]
+		_, err := ld.dec.Token()
+		if err != nil {
+			return Leg{}, ld.decodeError(err)
+		}
+		return Leg{}, ld.end()
+	}
+
+	raw := json.RawMessage{}
+	err := ld.dec.Decode(&raw)
+	if err != nil {
+		return Leg{}, ld.decodeError(err)
+	}
+
+	leg := Leg{}
+	err = leg.UnmarshalJSON(raw)
+	if err != nil {
+		// errors inside a leg are relative to where it starts
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			typeErr.Offset += ld.dec.InputOffset() - int64(len(raw))
+		}
+		return Leg{}, ld.decodeError(err)
+	}
+	ld.index++
+	return leg, nil
+}
+
+// end makes sure nothing but whitespace follows the list, returning io.EOF
+// when that's so the way json.Unmarshal rejects anything after the value
+func (ld *LegDecoder) end() error {
+	ld.done = true
+	token, err := ld.dec.Token()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return ld.decodeError(err)
+	}
+	// a second JSON value is valid as far as the decoder is concerned
+	return &LegDecodeError{
+		Index:  ld.index,
+		Offset: ld.dec.InputOffset(),
+		Err:    fmt.Errorf("unexpected %s after the list of legs", jsonKind(token)),
+	}
+}
+
+// decodeError wraps err with the position of the leg being read
+func (ld *LegDecoder) decodeError(err error) error {
+	ld.done = true
+	decodeErr := &LegDecodeError{Index: ld.index, Offset: -1, Err: err}
+	syntaxErr := &json.SyntaxError{}
+	typeErr := &json.UnmarshalTypeError{}
+	if errors.As(err, &syntaxErr) {
+		decodeErr.Offset = syntaxErr.Offset
+	} else if errors.As(err, &typeErr) {
+		decodeErr.Offset = typeErr.Offset
+	}
+	return decodeErr
+}
@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegDecoder(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	ld := models.NewLegDecoder(strings.NewReader(`[["IND", "EWR"], {"from": "SFO", "to": "IND", "flight": "UA1"}]`))
+
+	leg, err := ld.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"IND", "EWR"}, leg.Pair())
+
+	leg, err = ld.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "UA1", leg.Flight)
+
+	// io.EOF at the end, and every time after
+	_, err = ld.Next()
+	assert.True(t, errors.Is(err, io.EOF))
+	_, err = ld.Next()
+	assert.True(t, errors.Is(err, io.EOF))
+
+	// a null list has no legs
+	_, err = models.NewLegDecoder(strings.NewReader(`null`)).Next()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestLegDecoderErrors(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input  string
+		index  int
+		offset int64
+	}{
+		// not a list
+		{input: `{"from": "IND"}`, index: 0, offset: 1},
+		// a number where an airport should be, 4 bytes into the second leg
+		{input: `[["IND", "EWR"], [1, "IND"]]`, index: 1, offset: 19},
+		// cut off
+		{input: `[["IND", "EWR"], ["SFO"`, index: 1, offset: -1},
+		// empty
+		{input: ``, index: 0, offset: -1},
+		// anything after the list, the offset is where it starts
+		{input: `[["IND", "EWR"]] garbage`, index: 1, offset: 18},
+		{input: `[["IND", "EWR"]] [["EWR", "SFO"]]`, index: 1, offset: 18},
+		{input: `null 7`, index: 0, offset: 6},
+	} {
+		ld := models.NewLegDecoder(strings.NewReader(tc.input))
+		var err error
+		for err == nil {
+			_, err = ld.Next()
+		}
+
+		decodeErr := &models.LegDecodeError{}
+		assert.True(t, errors.As(err, &decodeErr), tc.input)
+		assert.Equal(t, tc.index, decodeErr.Index, tc.input)
+		assert.Equal(t, tc.offset, decodeErr.Offset, tc.input)
+	}
+}
+
+func TestSolveStream(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	input := `[["IND", "ewr"], {"from": "KSFO", "to": "ATL"}, ["GSO", "IND"], ["ATL", "GSO"]]`
+	fi, flightOutput, err := models.SolveStream(strings.NewReader(input), airports.Default())
+	assert.Nil(t, err)
+	assert.Equal(t, models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO", "IND"}, {"ATL", "GSO"}}, fi)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+	assert.Equal(t, []int{1, 3, 2, 0}, flightOutput.LegOrder)
+	assert.Len(t, flightOutput.Normalizations, 2)
+
+	// reading stops at the first duplicate, which still gets reported
+	input = `[["IND", "EWR"], ["IND", "SFO"], ["hello"]]`
+	fi, _, err = models.SolveStream(strings.NewReader(input), nil)
+	duplicate := &models.DuplicateDepartureError{}
+	assert.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, []int{0, 1}, duplicate.Indexes)
+	assert.Len(t, fi, 2)
+}
+
+func TestSolveStreamMatchesHashMap(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// a long chain given in reverse
+	fi := models.FlightsInput{}
+	for i := 999; i > 0; i-- {
+		fi = append(fi, []string{fmt.Sprintf("A%d", i-1), fmt.Sprintf("A%d", i)})
+	}
+	data, err := json.Marshal(fi)
+	assert.Nil(t, err)
+
+	_, streamed, err := models.SolveStream(strings.NewReader(string(data)), nil)
+	assert.Nil(t, err)
+	expected, err := fi.FindStartAndEndFlightHashMap()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, streamed)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// Distance is a great-circle distance in every unit we report
+type Distance struct {
+	Kilometers    float64
+	Miles         float64
+	NauticalMiles float64
+}
+
+// newDistance converts kilometers into a Distance
+func newDistance(kilometers float64) Distance {
+	return Distance{
+		Kilometers:    kilometers,
+		Miles:         kilometers / airports.KilometersPerMile,
+		NauticalMiles: kilometers / airports.KilometersPerNauticalMile,
+	}
+}
+
+// LegDistance is how far one flight goes
+type LegDistance struct {
+	// LegIndex is the position of the flight in the input
+	LegIndex int
+	From     string
+	To       string
+	Distance Distance
+}
+
+// TripDistance is the distance of every flight on a path and their total
+type TripDistance struct {
+	Legs  []LegDistance
+	Total Distance
+}
+
+// Distance returns the great-circle distance of every flight in order,
+// order being the LegOrder of a solved FlightOutput, looking airports up in db.
+// Any airport db doesn't know about gives an *UnknownAirportsError.
+func (fi FlightsInput) Distance(order []int, db *airports.DB) (TripDistance, error) {
+	// only the flights on the path need to be known
+	onPath := make(FlightsInput, len(order))
+	for n, i := range order {
+		onPath[n] = fi[i]
+	}
+	err := onPath.ValidateAirports(db)
+	unknown := &UnknownAirportsError{}
+	if errors.As(err, &unknown) {
+		// point back at the flights' positions in fi
+		for n, i := range unknown.Indexes {
+			unknown.Indexes[n] = order[i]
+		}
+		return TripDistance{}, unknown
+	}
+
+	trip := TripDistance{Legs: []LegDistance{}}
+	total := 0.0
+	for _, i := range order {
+		from, _ := db.Lookup(fi[i][0])
+		to, _ := db.Lookup(fi[i][1])
+		kilometers := airports.GreatCircleKilometers(from, to)
+		total += kilometers
+		trip.Legs = append(trip.Legs, LegDistance{
+			LegIndex: i,
+			From:     fi[i][0],
+			To:       fi[i][1],
+			Distance: newDistance(kilometers),
+		})
+	}
+	trip.Total = newDistance(total)
+	return trip, nil
+}
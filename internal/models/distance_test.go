@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	input := models.FlightsInput{{"JFK", "LHR"}, {"SFO", "JFK"}}
+	flightOutput, err := input.FindStartAndEndFlightHashMap()
+	assert.Nil(t, err)
+
+	trip, err := input.Distance(flightOutput.LegOrder, airports.Default())
+	assert.Nil(t, err)
+	assert.Len(t, trip.Legs, 2)
+
+	// SFO -> JFK comes first even though it's second in the input
+	assert.Equal(t, 1, trip.Legs[0].LegIndex)
+	assert.Equal(t, "SFO", trip.Legs[0].From)
+	assert.Equal(t, "JFK", trip.Legs[0].To)
+	assert.InDelta(t, 4152, trip.Legs[0].Distance.Kilometers, 10)
+	assert.InDelta(t, 2580, trip.Legs[0].Distance.Miles, 10)
+	assert.InDelta(t, 2242, trip.Legs[0].Distance.NauticalMiles, 10)
+
+	assert.InDelta(t, trip.Legs[0].Distance.Kilometers+trip.Legs[1].Distance.Kilometers, trip.Total.Kilometers, 0.001)
+	assert.InDelta(t, trip.Total.Kilometers/1.609344, trip.Total.Miles, 0.001)
+}
+
+func TestDistanceUnknownAirport(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the unknown airport's flight is blamed by its position in the input
+	input := models.FlightsInput{{"JFK", "XYZ"}, {"SFO", "JFK"}}
+	_, err := input.Distance([]int{1, 0}, airports.Default())
+
+	unknown := &models.UnknownAirportsError{}
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"XYZ"}, unknown.Airports)
+	assert.Equal(t, []int{0}, unknown.Indexes)
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// sentinel errors for every way a FlightsInput can fail to solve,
+// use errors.Is to check for one of these and errors.As with the
+// matching *Error type below to get at the offending airports and indexes
+var (
+	ErrMalformedLeg         = errors.New("malformed flight leg")
+	ErrDuplicateDeparture   = errors.New("duplicate departure airport")
+	ErrDuplicateArrival     = errors.New("duplicate arrival airport")
+	ErrDisconnectedSegments = errors.New("disconnected flight segments")
+	ErrLoop                 = errors.New("flight path contains a loop")
+	ErrUnbalancedAirports   = errors.New("airports departed from and arrived at a mismatched number of times")
+	ErrUnknownAirport       = errors.New("unknown airport code")
+	ErrEmptyInput           = errors.New("no flights given")
+)
+
+// EmptyInputError is returned when there are no flights to find a path through
+type EmptyInputError struct{}
+
+func (e *EmptyInputError) Error() string {
+	return "No flights were given, at least one is needed to find a path."
+}
+
+// Is makes errors.Is(err, ErrEmptyInput) match
+func (e *EmptyInputError) Is(target error) bool {
+	return target == ErrEmptyInput
+}
+
+// MalformedLegError is returned when a flight isn't exactly one departure and one arrival
+type MalformedLegError struct {
+	// Index is the position of the flight in the input
+	Index int
+	Leg   []string
+}
+
+func (e *MalformedLegError) Error() string {
+	return fmt.Sprintf("Item %v does not have exactly two airports.", e.Leg)
+}
+
+// Is makes errors.Is(err, ErrMalformedLeg) match
+func (e *MalformedLegError) Is(target error) bool {
+	return target == ErrMalformedLeg
+}
+
+// DuplicateDepartureError is returned when more than one flight leaves the same airport
+type DuplicateDepartureError struct {
+	Airport string
+	// Indexes are the positions in the input of every flight departing Airport
+	Indexes []int
+}
+
+func (e *DuplicateDepartureError) Error() string {
+	return fmt.Sprintf("Departure airport %v appears more than once in the given flight plan.", e.Airport)
+}
+
+// Is makes errors.Is(err, ErrDuplicateDeparture) match
+func (e *DuplicateDepartureError) Is(target error) bool {
+	return target == ErrDuplicateDeparture
+}
+
+// DuplicateArrivalError is returned when more than one flight lands at the same airport
+type DuplicateArrivalError struct {
+	Airport string
+	// Indexes are the positions in the input of every flight arriving at Airport
+	Indexes []int
+}
+
+func (e *DuplicateArrivalError) Error() string {
+	return fmt.Sprintf("Arrival airport %v appears more than once in the given flight plan.", e.Airport)
+}
+
+// Is makes errors.Is(err, ErrDuplicateArrival) match
+func (e *DuplicateArrivalError) Is(target error) bool {
+	return target == ErrDuplicateArrival
+}
+
+// DisconnectedSegmentsError is returned when the flights form more than one chain
+type DisconnectedSegmentsError struct {
+	// Segments are the chains in the order they'd be joined in, loops last
+	Segments []Segment
+}
+
+func (e *DisconnectedSegmentsError) Error() string {
+	msg := "Unable to find a connecting path for given flights."
+	for _, gap := range e.Gaps() {
+		msg += " " + gap.String()
+	}
+	return msg
+}
+
+// Gaps is every place one chain ends without the next one starting there
+func (e *DisconnectedSegmentsError) Gaps() []Gap {
+	return findGaps(e.Segments)
+}
+
+// SuggestedLegs is the fewest flights that would join every chain
+// into one itinerary, one per gap. Loops can't be joined by adding
+// flights so they're left out.
+func (e *DisconnectedSegmentsError) SuggestedLegs() FlightsInput {
+	suggested := FlightsInput{}
+	for _, gap := range e.Gaps() {
+		suggested = append(suggested, gap.SuggestedLeg())
+	}
+	return suggested
+}
+
+// Is makes errors.Is(err, ErrDisconnectedSegments) match
+func (e *DisconnectedSegmentsError) Is(target error) bool {
+	return target == ErrDisconnectedSegments
+}
+
+// LoopError is returned when flights lead back to an airport already in the path
+type LoopError struct {
+	// Airports is the loop in travel order, starting and ending on the same airport
+	Airports []string
+	// Indexes are the positions in the input of the flights making up the loop
+	Indexes []int
+}
+
+func (e *LoopError) Error() string {
+	return "Duplicates found in flight path. There's a complete or partial loop in given flight plan, or duplicate arrival/departure airports."
+}
+
+// Is makes errors.Is(err, ErrLoop) match
+func (e *LoopError) Is(target error) bool {
+	return target == ErrLoop
+}
+
+// UnbalancedAirportsError is returned by the Eulerian solver when airports are
+// departed from and arrived at a different number of times than one trip allows
+type UnbalancedAirportsError struct {
+	Airports []string
+	// Indexes are the positions in the input of every flight touching one of Airports
+	Indexes []int
+}
+
+func (e *UnbalancedAirportsError) Error() string {
+	return fmt.Sprintf("Airports %v are departed from and arrived at a mismatched number of times, the flights can't form a single trip.", e.Airports)
+}
+
+// Is makes errors.Is(err, ErrUnbalancedAirports) match
+func (e *UnbalancedAirportsError) Is(target error) bool {
+	return target == ErrUnbalancedAirports
+}
+
+// UnknownAirportsError is returned when flights use codes that aren't in the airport reference data
+type UnknownAirportsError struct {
+	// Airports are the unknown codes in the order they first appear
+	Airports []string
+	// Indexes are the positions in the input of every flight using one of Airports
+	Indexes []int
+	// Curated is set when the codes were checked against only the curated
+	// airports embedded with the server, see airports.DB.Curated
+	Curated bool
+}
+
+func (e *UnknownAirportsError) Error() string {
+	if e.Curated {
+		return fmt.Sprintf("Airport codes %v are not in the curated set of major airports this server knows, only those are available.", e.Airports)
+	}
+	return fmt.Sprintf("Airport codes %v are not known IATA or ICAO codes.", e.Airports)
+}
+
+// Is makes errors.Is(err, ErrUnknownAirport) match
+func (e *UnknownAirportsError) Is(target error) bool {
+	return target == ErrUnknownAirport
+}
@@ -0,0 +1,164 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsMalformedLeg(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"SFO", "ATL"}, {"IND"}}
+	for _, solver := range []models.PathSolverFunc{
+		models.FlightsInput.FindStartAndEndFlightNaive,
+		models.FlightsInput.FindStartAndEndFlightLinkedList,
+		models.FlightsInput.FindStartAndEndFlightHashMap,
+	} {
+		_, err := solver(fi)
+		assert.True(t, errors.Is(err, models.ErrMalformedLeg))
+
+		malformed := &models.MalformedLegError{}
+		assert.True(t, errors.As(err, &malformed))
+		assert.Equal(t, 1, malformed.Index)
+		assert.Equal(t, []string{"IND"}, malformed.Leg)
+	}
+}
+
+func TestErrorsDuplicateDeparture(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"SLC", "JFK"}, {"SFO", "ATL"}, {"SLC", "SFO"}}
+	_, err := fi.FindStartAndEndFlightHashMap()
+	assert.True(t, errors.Is(err, models.ErrDuplicateDeparture))
+	assert.False(t, errors.Is(err, models.ErrDuplicateArrival))
+
+	duplicate := &models.DuplicateDepartureError{}
+	assert.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "SLC", duplicate.Airport)
+	assert.Equal(t, []int{0, 2}, duplicate.Indexes)
+}
+
+func TestErrorsDuplicateArrival(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"SLC", "JFK"}, {"JFK", "ATL"}, {"SFO", "ATL"}}
+	_, err := fi.FindStartAndEndFlightLinkedList()
+	assert.True(t, errors.Is(err, models.ErrDuplicateArrival))
+
+	duplicate := &models.DuplicateArrivalError{}
+	assert.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "ATL", duplicate.Airport)
+	assert.Equal(t, []int{1, 2}, duplicate.Indexes)
+}
+
+func TestErrorsDisconnectedSegments(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"IND", "EWR"}, {"SLC", "JFK"}, {"ATL", "IND"}}
+	for _, solver := range []models.PathSolverFunc{
+		models.FlightsInput.FindStartAndEndFlightLinkedList,
+		models.FlightsInput.FindStartAndEndFlightHashMap,
+	} {
+		_, err := solver(fi)
+		assert.True(t, errors.Is(err, models.ErrDisconnectedSegments))
+
+		disconnected := &models.DisconnectedSegmentsError{}
+		assert.True(t, errors.As(err, &disconnected))
+		assert.Equal(t, []models.Segment{
+			{Departure: "SLC", Arrival: "JFK", Airports: []string{"SLC", "JFK"}, Indexes: []int{1}},
+			{Departure: "ATL", Arrival: "EWR", Airports: []string{"ATL", "IND", "EWR"}, Indexes: []int{2, 0}},
+		}, disconnected.Segments)
+	}
+}
+
+func TestErrorsDisconnectedSegmentsGaps(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// three chains and a loop, the loop can't be joined so it has no gap
+	fi := models.FlightsInput{{"IND", "EWR"}, {"SLC", "JFK"}, {"ORD", "DEN"}, {"DEN", "ORD"}, {"SFO", "ATL"}}
+	_, err := fi.FindStartAndEndFlightHashMap()
+
+	disconnected := &models.DisconnectedSegmentsError{}
+	assert.True(t, errors.As(err, &disconnected))
+	assert.Equal(t, []models.Gap{
+		{Arrival: "EWR", Departure: "SLC", ArrivingLegIndex: 0, DepartingLegIndex: 1},
+		{Arrival: "JFK", Departure: "SFO", ArrivingLegIndex: 1, DepartingLegIndex: 4},
+	}, disconnected.Gaps())
+	assert.Equal(t, models.FlightsInput{{"EWR", "SLC"}, {"JFK", "SFO"}}, disconnected.SuggestedLegs())
+	assert.Equal(t, "Unable to find a connecting path for given flights. "+
+		"Segment ending at EWR and segment starting at SLC are not connected. "+
+		"Segment ending at JFK and segment starting at SFO are not connected.", err.Error())
+
+	// adding the suggested legs gives one itinerary without the loop
+	fixed := models.FlightsInput{{"IND", "EWR"}, {"SLC", "JFK"}, {"SFO", "ATL"}}
+	fixed = append(fixed, disconnected.SuggestedLegs()...)
+	flightOutput, err := fixed.FindStartAndEndFlightHashMap()
+	assert.Nil(t, err)
+	assert.Equal(t, "IND - EWR - SLC - JFK - SFO - ATL", flightOutput.Path)
+}
+
+func TestErrorsLoop(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"IND", "EWR"}, {"EWR", "ATL"}, {"ATL", "IND"}}
+	for _, solver := range []models.PathSolverFunc{
+		models.FlightsInput.FindStartAndEndFlightLinkedList,
+		models.FlightsInput.FindStartAndEndFlightHashMap,
+	} {
+		_, err := solver(fi)
+		assert.True(t, errors.Is(err, models.ErrLoop))
+
+		loop := &models.LoopError{}
+		assert.True(t, errors.As(err, &loop))
+		assert.Equal(t, []string{"IND", "EWR", "ATL", "IND"}, loop.Airports)
+		assert.Equal(t, []int{0, 1, 2}, loop.Indexes)
+	}
+
+	// the naive solver can only tell us it's a loop
+	_, err := fi.FindStartAndEndFlightNaive()
+	assert.True(t, errors.Is(err, models.ErrLoop))
+}
+
+func TestErrorsEmptyInput(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	for _, fi := range []models.FlightsInput{{}, nil} {
+		for _, solver := range []func(models.FlightsInput) (models.FlightOutput, error){
+			models.FlightsInput.FindStartAndEndFlightHashMap,
+			models.FlightsInput.FindStartAndEndFlightLinkedList,
+			models.FlightsInput.FindStartAndEndFlightNaive,
+			models.FlightsInput.FindStartAndEndFlightEulerian,
+		} {
+			flightOutput, err := solver(fi)
+			assert.True(t, errors.Is(err, models.ErrEmptyInput))
+			// no flights isn't a loop
+			assert.False(t, errors.Is(err, models.ErrLoop))
+			assert.Equal(t, err.Error(), flightOutput.ErrorInformation)
+		}
+
+		_, err := fi.FindItineraries()
+		assert.True(t, errors.Is(err, models.ErrEmptyInput))
+		assert.True(t, errors.Is(fi.Validate(), models.ErrEmptyInput))
+		assert.True(t, errors.Is(fi.ValidateItineraries(), models.ErrEmptyInput))
+	}
+
+	_, err := models.NewIncrementalItinerary().Solve()
+	assert.True(t, errors.Is(err, models.ErrEmptyInput))
+}
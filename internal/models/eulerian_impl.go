@@ -0,0 +1,271 @@
+package models
+
+import "sort"
+
+// FindStartAndEndFlightEulerian treats the flights as a directed multigraph
+// and reconstructs the trip as an Eulerian trail with Hierholzer's algorithm,
+// so unlike the other solvers the same airport can be departed from and
+// arrived at more than once, e.g. connecting through a hub twice or a
+// SFO -> ORD -> SFO round trip. A round trip is assumed to start where the
+// first flight in the input departs. When more than one ordering of the
+// flights is possible the lexicographically smallest one is returned and
+// Ambiguous is set.
+func (fi FlightsInput) FindStartAndEndFlightEulerian() (fo FlightOutput, err error) {
+	for i, flightPair := range fi {
+		// ensure all flightPairs are exactly 2 long
+		if len(flightPair) != 2 {
+			err = &MalformedLegError{Index: i, Leg: flightPair}
+			fo.ErrorInformation = err.Error()
+			return fo, err
+		}
+	}
+
+	components := connectedComponents(fi)
+
+	// legs that share no airports at all can never be one trip,
+	// but each part can still be reported as its own trail
+	if len(components) > 1 {
+		segments := []Segment{}
+		for _, component := range components {
+			segment, _, err := eulerianTrail(fi, component)
+			if err != nil {
+				fo.ErrorInformation = err.Error()
+				return fo, err
+			}
+			segments = append(segments, segment)
+		}
+		err = &DisconnectedSegmentsError{Segments: segments}
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+
+	// an empty input has no components
+	if len(components) == 0 {
+		err = &EmptyInputError{}
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+
+	segment, ambiguous, err := eulerianTrail(fi, components[0])
+	if err != nil {
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+
+	fo.FinalDepartureAirport = segment.Departure
+	fo.FinalArrivalAirport = segment.Arrival
+	fo.CalculateResult = []string{
+		segment.Departure,
+		segment.Arrival,
+	}
+	fo.Path = concatPath(segment.Airports)
+	fo.LegOrder = segment.Indexes
+	fo.Ambiguous = ambiguous
+
+	return fo, nil
+}
+
+// connectedComponents groups the indexes of flights that share airports,
+// ignoring direction. Components are ordered by their first flight in the input.
+func connectedComponents(fi FlightsInput) (components [][]int) {
+	// union find over airports
+	parent := make(map[string]string)
+	var find func(airport string) string
+	find = func(airport string) string {
+		p, ok := parent[airport]
+		if !ok || p == airport {
+			parent[airport] = airport
+			return airport
+		}
+		root := find(p)
+		parent[airport] = root
+		return root
+	}
+	for _, flightPair := range fi {
+		parent[find(flightPair[0])] = find(flightPair[1])
+	}
+
+	// root airport -> position in components
+	componentIndex := make(map[string]int)
+	for i, flightPair := range fi {
+		root := find(flightPair[0])
+		c, ok := componentIndex[root]
+		if !ok {
+			c = len(components)
+			componentIndex[root] = c
+			components = append(components, nil)
+		}
+		components[c] = append(components[c], i)
+	}
+	return components
+}
+
+// eulerianTrail orders the flights at indexes, which must all be connected,
+// into a single trail using every flight once. ambiguous is true when more
+// than one sequence of airports would also use every flight once.
+func eulerianTrail(fi FlightsInput, indexes []int) (segment Segment, ambiguous bool, err error) {
+	// departures - arrivals per airport
+	balance := make(map[string]int)
+	// airport -> indexes of flights leaving it
+	adjacency := make(map[string][]int)
+	for _, i := range indexes {
+		balance[fi[i][0]]++
+		balance[fi[i][1]]--
+		adjacency[fi[i][0]] = append(adjacency[fi[i][0]], i)
+	}
+
+	// a trail starts at the only airport departed once more than it's
+	// arrived at and ends at the only one arrived at once more,
+	// every other airport has to balance out
+	starts := []string{}
+	ends := []string{}
+	unbalanced := []string{}
+	for airport, b := range balance {
+		switch {
+		case b == 1:
+			starts = append(starts, airport)
+		case b == -1:
+			ends = append(ends, airport)
+		case b != 0:
+			unbalanced = append(unbalanced, airport)
+		}
+	}
+	if len(starts) > 1 || len(ends) > 1 {
+		unbalanced = append(unbalanced, starts...)
+		unbalanced = append(unbalanced, ends...)
+	}
+	if len(unbalanced) > 0 {
+		sort.Strings(unbalanced)
+		return segment, false, newUnbalancedAirportsError(fi, indexes, unbalanced)
+	}
+
+	// a round trip starts where the first flight given departs
+	start := fi[indexes[0]][0]
+	if len(starts) == 1 {
+		start = starts[0]
+	}
+
+	// smallest arrival airport first, then input order for identical flights
+	for airport, legs := range adjacency {
+		sort.SliceStable(legs, func(a, b int) bool {
+			return fi[legs[a]][1] < fi[legs[b]][1]
+		})
+		adjacency[airport] = legs
+	}
+	airports, legs := hierholzer(fi, adjacency, start, false)
+
+	// the smallest and largest orderings only match when there's just one
+	reversedAirports, _ := hierholzer(fi, adjacency, start, true)
+	for i := range airports {
+		if airports[i] != reversedAirports[i] {
+			ambiguous = true
+			break
+		}
+	}
+
+	segment = Segment{
+		Departure: airports[0],
+		Arrival:   airports[len(airports)-1],
+		Airports:  airports,
+		Indexes:   legs,
+	}
+	return segment, ambiguous, nil
+}
+
+// hierholzer walks every flight in adjacency once starting at start,
+// taking the smallest arrival airport first, or the largest if largestFirst
+// is set. It returns the airports and flight indexes in travel order.
+func hierholzer(fi FlightsInput, adjacency map[string][]int, start string, largestFirst bool) (airports []string, legs []int) {
+	type step struct {
+		airport string
+		// flight taken to get to airport, -1 for the start
+		leg int
+	}
+
+	// how many flights out of each airport have been taken
+	taken := make(map[string]int)
+	stack := []step{{airport: start, leg: -1}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		out := adjacency[top.airport]
+		if taken[top.airport] < len(out) {
+			next := out[taken[top.airport]]
+			if largestFirst {
+				next = out[len(out)-1-taken[top.airport]]
+			}
+			taken[top.airport]++
+			stack = append(stack, step{airport: fi[next][1], leg: next})
+			continue
+		}
+
+		// nothing left to take from here, so it's next from the end of the trail
+		stack = stack[:len(stack)-1]
+		airports = append(airports, top.airport)
+		if top.leg >= 0 {
+			legs = append(legs, top.leg)
+		}
+	}
+
+	// we built the trail back to front
+	for i, j := 0, len(airports)-1; i < j; i, j = i+1, j-1 {
+		airports[i], airports[j] = airports[j], airports[i]
+	}
+	for i, j := 0, len(legs)-1; i < j; i, j = i+1, j-1 {
+		legs[i], legs[j] = legs[j], legs[i]
+	}
+	return airports, legs
+}
+
+// newUnbalancedAirportsError blames every flight at indexes touching one of airports
+func newUnbalancedAirportsError(fi FlightsInput, indexes []int, airports []string) error {
+	blamed := make(map[string]bool, len(airports))
+	for _, airport := range airports {
+		blamed[airport] = true
+	}
+
+	err := &UnbalancedAirportsError{Airports: airports}
+	for _, i := range indexes {
+		if blamed[fi[i][0]] || blamed[fi[i][1]] {
+			err.Indexes = append(err.Indexes, i)
+		}
+	}
+	return err
+}
+
+// eulerianSolver is the eulerian PathSolver, it validates by reporting every
+// malformed flight before checking the rest the way it solves
+type eulerianSolver struct{}
+
+// Solve calls FindStartAndEndFlightEulerian
+func (eulerianSolver) Solve(fi FlightsInput) (FlightOutput, error) {
+	return fi.FindStartAndEndFlightEulerian()
+}
+
+// Validate reports every malformed flight, or failing that every part of
+// the flights with unbalanced airports, or that the parts don't connect
+func (eulerianSolver) Validate(fi FlightsInput) error {
+	problems := []error{}
+	for i, flightPair := range fi {
+		if len(flightPair) != 2 {
+			problems = append(problems, &MalformedLegError{Index: i, Leg: flightPair})
+		}
+	}
+	if len(problems) > 0 {
+		return validationResult(problems)
+	}
+
+	// check every part, FindStartAndEndFlightEulerian stops at the first bad one
+	segments := []Segment{}
+	for _, component := range connectedComponents(fi) {
+		segment, _, err := eulerianTrail(fi, component)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	if len(problems) == 0 && len(segments) > 1 {
+		problems = append(problems, &DisconnectedSegmentsError{Segments: segments})
+	}
+	return validationResult(problems)
+}
@@ -0,0 +1,143 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEulerianComplex(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{}
+
+	inputData := `[
+  ["IND", "EWR"], 
+  ["SFO", "ATL"], 
+  ["GSO", "IND"], 
+  ["ATL", "GSO"]
+  ]`
+	err := json.Unmarshal([]byte(inputData), &fi)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "EWR"}, flightOutput.CalculateResult)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+	assert.False(t, flightOutput.Ambiguous)
+}
+
+func TestEulerianRoundTrip(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"SFO", "ORD"}, {"ORD", "SFO"}}
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "SFO"}, flightOutput.CalculateResult)
+	assert.Equal(t, "SFO - ORD - SFO", flightOutput.Path)
+	assert.False(t, flightOutput.Ambiguous)
+}
+
+func TestEulerianHubTwice(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// ORD -> LAX first would leave JFK stranded, so there's only one ordering
+	fi := models.FlightsInput{{"ORD", "LAX"}, {"JFK", "ORD"}, {"SFO", "ORD"}, {"ORD", "JFK"}}
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "LAX"}, flightOutput.CalculateResult)
+	assert.Equal(t, "SFO - ORD - JFK - ORD - LAX", flightOutput.Path)
+	assert.False(t, flightOutput.Ambiguous)
+}
+
+func TestEulerianRepeatedLeg(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// flying SFO -> ORD twice can be ordered two ways, but they're the same trip
+	fi := models.FlightsInput{{"SFO", "ORD"}, {"ORD", "SFO"}, {"SFO", "ORD"}}
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ORD - SFO - ORD", flightOutput.Path)
+	assert.False(t, flightOutput.Ambiguous)
+}
+
+func TestEulerianAmbiguous(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the DEN and JFK side trips out of ORD can happen in either order
+	fi := models.FlightsInput{{"SFO", "ORD"}, {"ORD", "JFK"}, {"JFK", "ORD"}, {"ORD", "DEN"}, {"DEN", "ORD"}, {"ORD", "LAX"}}
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ORD - DEN - ORD - JFK - ORD - LAX", flightOutput.Path)
+	assert.True(t, flightOutput.Ambiguous)
+}
+
+func TestEulerianUnbalanced(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// ATL is departed from twice but only arrived at once
+	fi := models.FlightsInput{{"SFO", "ATL"}, {"ATL", "SLC"}, {"ATL", "GSO"}}
+
+	_, err := fi.FindStartAndEndFlightEulerian()
+	assert.True(t, errors.Is(err, models.ErrUnbalancedAirports))
+
+	unbalanced := &models.UnbalancedAirportsError{}
+	assert.True(t, errors.As(err, &unbalanced))
+	assert.Equal(t, []string{"ATL", "GSO", "SFO", "SLC"}, unbalanced.Airports)
+	assert.Equal(t, []int{0, 1, 2}, unbalanced.Indexes)
+}
+
+func TestEulerianDisconnected(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"SFO", "ORD"}, {"JFK", "BOS"}, {"ORD", "SFO"}}
+
+	_, err := fi.FindStartAndEndFlightEulerian()
+	assert.True(t, errors.Is(err, models.ErrDisconnectedSegments))
+
+	disconnected := &models.DisconnectedSegmentsError{}
+	assert.True(t, errors.As(err, &disconnected))
+	assert.Equal(t, []models.Segment{
+		{Departure: "SFO", Arrival: "SFO", Airports: []string{"SFO", "ORD", "SFO"}, Indexes: []int{0, 2}},
+		{Departure: "JFK", Arrival: "BOS", Airports: []string{"JFK", "BOS"}, Indexes: []int{1}},
+	}, disconnected.Segments)
+}
+
+func TestEulerianInvalidList(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"IND"}, {"SFO", "ATL"}}
+
+	flightOutput, err := fi.FindStartAndEndFlightEulerian()
+	assert.True(t, errors.Is(err, models.ErrMalformedLeg))
+	assert.Contains(t, flightOutput.ErrorInformation, "Item [IND] does not have exactly two airports.")
+}
@@ -0,0 +1,28 @@
+package models
+
+import "strings"
+
+// FindStartAndEndFlightHashMap builds a departure -> flight and an
+// arrival -> flight map in a single pass over the flights with a PathBuilder,
+// finds the only departure airport that is never an arrival and walks the
+// chain from there.
+// The linked list implementation re-scans orphaned flights until they attach,
+// which goes quadratic on unlucky orderings; this one is O(n) for any input.
+func (fi FlightsInput) FindStartAndEndFlightHashMap() (fo FlightOutput, err error) {
+	// validate our FlightsInput struct as the maps are built
+	pb := NewPathBuilder()
+	for _, flightPair := range fi {
+		err = pb.Add(flightPair)
+		if err != nil {
+			fo.ErrorInformation = err.Error()
+			return fo, err
+		}
+	}
+	return pb.Solve()
+}
+
+// turn a slice from "['EWR', 'SFO', 'ATL']" -> "EWR - SFO - ATL"
+// same output as concatLinkedList without re-formatting the string per airport
+func concatPath(airports []string) string {
+	return strings.Join(airports, " - ")
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// airportNode is one airport in an IncrementalItinerary's chains
+type airportNode struct {
+	airport    string
+	prev, next *airportNode
+	// leg is the position in the input of the flight leaving this airport, -1 when none has yet
+	leg int
+	// head is set on the last airport of a chain and points at its first,
+	// tail is set on the first airport of a chain and points at its last
+	head, tail *airportNode
+}
+
+// IncrementalItinerary keeps flights joined into chains as they're added,
+// so a stored itinerary doesn't have to be solved from scratch every time a
+// flight is appended. Each flight either starts a new chain, extends the
+// front or back of one, or joins two into one, all without walking them.
+// Flights that can't be part of a single trip, because they repeat a
+// departure or arrival or close a loop, are rejected as they're added.
+// It's what FindStartAndEndFlightLinkedList does with container/list and
+// itemMap, except chains are linked directly so joining two is a couple of
+// pointer swaps instead of copying one onto the other.
+type IncrementalItinerary struct {
+	fi FlightsInput
+	// airport -> its place in a chain
+	nodes map[string]*airportNode
+	// first airport of every chain
+	heads map[string]*airportNode
+}
+
+// NewIncrementalItinerary returns an IncrementalItinerary without any flights
+func NewIncrementalItinerary() *IncrementalItinerary {
+	return &IncrementalItinerary{
+		fi:    FlightsInput{},
+		nodes: make(map[string]*airportNode),
+		heads: make(map[string]*airportNode),
+	}
+}
+
+// Add joins the next flight onto the chains in constant time. It returns the
+// errors validateFlightsInput would for the flight, or a *LoopError when the
+// flight joins the two ends of one chain. A flight that's rejected isn't added.
+func (ii *IncrementalItinerary) Add(flightPair []string) error {
+	i := len(ii.fi)
+	// ensure all flightPairs are exactly 2 long
+	if len(flightPair) != 2 {
+		return &MalformedLegError{Index: i, Leg: flightPair}
+	}
+	departure, arrival := flightPair[0], flightPair[1]
+
+	from, fromOK := ii.nodes[departure]
+	to, toOK := ii.nodes[arrival]
+
+	// only the last airport of a chain can be departed from
+	// and only the first can be arrived at
+	if fromOK && from.next != nil {
+		return &DuplicateDepartureError{Airport: departure, Indexes: []int{from.leg, i}}
+	}
+	if toOK && to.prev != nil {
+		return &DuplicateArrivalError{Airport: arrival, Indexes: []int{to.prev.leg, i}}
+	}
+	if departure == arrival || (fromOK && toOK && from.head == to) {
+		err := &LoopError{Airports: []string{departure, arrival}, Indexes: []int{i}}
+		if departure != arrival {
+			// the loop is the whole chain plus this flight
+			chain := ii.walk(to)
+			err.Airports = append(chain.Airports, arrival)
+			err.Indexes = append(chain.Indexes, i)
+		}
+		return err
+	}
+
+	if !fromOK {
+		from = &airportNode{airport: departure, leg: -1}
+		from.head, from.tail = from, from
+		ii.nodes[departure] = from
+		ii.heads[departure] = from
+	}
+	if !toOK {
+		to = &airportNode{airport: arrival, leg: -1}
+		to.head, to.tail = to, to
+		ii.nodes[arrival] = to
+		ii.heads[arrival] = to
+	}
+
+	// from is the last airport of its chain and to is the first of
+	// another, linking them makes one chain out of the two
+	head, tail := from.head, to.tail
+	from.next, to.prev = to, from
+	from.leg = i
+	delete(ii.heads, arrival)
+	// only the ends of a chain keep pointers to each other
+	from.head, to.tail = nil, nil
+	head.tail, tail.head = tail, head
+
+	ii.fi = append(ii.fi, flightPair)
+	return nil
+}
+
+// Len returns how many flights have been added
+func (ii *IncrementalItinerary) Len() int {
+	return len(ii.fi)
+}
+
+// FlightsInput returns every flight added so far in the order they were added
+func (ii *IncrementalItinerary) FlightsInput() FlightsInput {
+	return ii.fi
+}
+
+// Complete reports whether the flights added so far form a single path
+func (ii *IncrementalItinerary) Complete() bool {
+	return len(ii.heads) == 1
+}
+
+// Segments returns every chain, ordered by the position of its first
+// flight in the input the way findSegments orders them
+func (ii *IncrementalItinerary) Segments() []Segment {
+	heads := make([]*airportNode, 0, len(ii.heads))
+	for _, head := range ii.heads {
+		heads = append(heads, head)
+	}
+	sort.Slice(heads, func(a, b int) bool {
+		return heads[a].leg < heads[b].leg
+	})
+
+	segments := make([]Segment, 0, len(heads))
+	for _, head := range heads {
+		segments = append(segments, ii.walk(head))
+	}
+	return segments
+}
+
+// walk follows the chain starting at head
+func (ii *IncrementalItinerary) walk(head *airportNode) Segment {
+	segment := Segment{Departure: head.airport}
+	node := head
+	for ; node.next != nil; node = node.next {
+		segment.Airports = append(segment.Airports, node.airport)
+		segment.Indexes = append(segment.Indexes, node.leg)
+	}
+	segment.Airports = append(segment.Airports, node.airport)
+	segment.Arrival = node.airport
+	return segment
+}
+
+// Solve returns the path of the flights added so far, or a
+// *DisconnectedSegmentsError when they're more than one chain
+func (ii *IncrementalItinerary) Solve() (fo FlightOutput, err error) {
+	segments := ii.Segments()
+	if len(segments) != 1 {
+		err = segmentsError(segments)
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+	path := segments[0].Airports
+
+	startFlight := path[0]
+	fo.FinalDepartureAirport = startFlight
+
+	endFlight := path[len(path)-1]
+	fo.FinalArrivalAirport = endFlight
+
+	fo.CalculateResult = []string{
+		startFlight,
+		endFlight,
+	}
+
+	fo.Path = concatPath(path)
+	fo.LegOrder = segments[0].Indexes
+
+	return fo, nil
+}
+
+// SolveLegs is Solve for when ls are the legs that were added, in the same
+// order, it fills in TimingConflicts and OrderedLegs the way Legs.Solve does.
+// The chains decide the order even when every leg has a time.
+func (ii *IncrementalItinerary) SolveLegs(ls Legs) (fo FlightOutput, err error) {
+	if len(ls) != ii.Len() {
+		return fo, fmt.Errorf("%d legs given for an itinerary of %d", len(ls), ii.Len())
+	}
+	fo, err = ii.Solve()
+	if err != nil {
+		return fo, err
+	}
+	ls.describe(&fo)
+	return fo, nil
+}
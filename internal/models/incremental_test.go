@@ -0,0 +1,129 @@
+package models_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementalItinerary(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	ii := models.NewIncrementalItinerary()
+	// a new chain, then one spliced onto its back, a separate chain,
+	// one spliced onto its front and finally one joining the two
+	for i, flightPair := range (models.FlightsInput{{"GSO", "IND"}, {"IND", "EWR"}, {"SFO", "ATL"}, {"LAX", "SFO"}, {"ATL", "GSO"}}) {
+		assert.Nil(t, ii.Add(flightPair))
+		assert.Equal(t, i+1, ii.Len())
+		assert.Equal(t, i != 2 && i != 3, ii.Complete(), i)
+	}
+
+	flightOutput, err := ii.Solve()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LAX", "EWR"}, flightOutput.CalculateResult)
+	assert.Equal(t, "LAX - SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+	assert.Equal(t, []int{3, 2, 4, 0, 1}, flightOutput.LegOrder)
+
+	// same answer as solving everything at once
+	expected, err := ii.FlightsInput().FindStartAndEndFlightHashMap()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, flightOutput)
+}
+
+func TestIncrementalItineraryConflicts(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	ii := models.NewIncrementalItinerary()
+	for _, flightPair := range (models.FlightsInput{{"SFO", "ATL"}, {"ATL", "GSO"}, {"IND", "EWR"}}) {
+		assert.Nil(t, ii.Add(flightPair))
+	}
+
+	err := ii.Add([]string{"SFO"})
+	malformed := &models.MalformedLegError{}
+	assert.True(t, errors.As(err, &malformed))
+	assert.Equal(t, 3, malformed.Index)
+
+	err = ii.Add([]string{"ATL", "JFK"})
+	departure := &models.DuplicateDepartureError{}
+	assert.True(t, errors.As(err, &departure))
+	assert.Equal(t, "ATL", departure.Airport)
+	assert.Equal(t, []int{1, 3}, departure.Indexes)
+
+	err = ii.Add([]string{"JFK", "EWR"})
+	arrival := &models.DuplicateArrivalError{}
+	assert.True(t, errors.As(err, &arrival))
+	assert.Equal(t, "EWR", arrival.Airport)
+	assert.Equal(t, []int{2, 3}, arrival.Indexes)
+
+	err = ii.Add([]string{"GSO", "SFO"})
+	loop := &models.LoopError{}
+	assert.True(t, errors.As(err, &loop))
+	assert.Equal(t, []string{"SFO", "ATL", "GSO", "SFO"}, loop.Airports)
+	assert.Equal(t, []int{0, 1, 3}, loop.Indexes)
+
+	err = ii.Add([]string{"JFK", "JFK"})
+	assert.True(t, errors.Is(err, models.ErrLoop))
+
+	// none of the rejected flights were added
+	assert.Equal(t, models.FlightsInput{{"SFO", "ATL"}, {"ATL", "GSO"}, {"IND", "EWR"}}, ii.FlightsInput())
+
+	_, err = ii.Solve()
+	disconnected := &models.DisconnectedSegmentsError{}
+	assert.True(t, errors.As(err, &disconnected))
+	assert.Equal(t, models.FlightsInput{{"GSO", "IND"}}, disconnected.SuggestedLegs())
+
+	// the suggested flight completes the itinerary
+	assert.Nil(t, ii.Add([]string{"GSO", "IND"}))
+	assert.True(t, ii.Complete())
+	flightOutput, err := ii.Solve()
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+}
+
+func TestIncrementalItineraryRandomOrder(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi, solution, path := generateRandomFlightPath(1000)
+	rand.Shuffle(len(fi), func(a, b int) {
+		fi[a], fi[b] = fi[b], fi[a]
+	})
+
+	ii := models.NewIncrementalItinerary()
+	for _, flightPair := range fi {
+		assert.Nil(t, ii.Add(flightPair))
+	}
+
+	flightOutput, err := ii.Solve()
+	assert.Nil(t, err)
+	assert.Equal(t, solution, flightOutput.CalculateResult)
+	assert.Equal(t, path, flightOutput.Path)
+}
+
+func BenchmarkIncrementalItinerary1000Flights(b *testing.B) {
+	fi, solution, path := generateRandomFlightPath(1000)
+	flightOutput := models.FlightOutput{}
+
+	// reset timer since we ran expensive setup funcs
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ii := models.NewIncrementalItinerary()
+		for _, flightPair := range fi {
+			_ = ii.Add(flightPair)
+		}
+		flightOutput, _ = ii.Solve()
+	}
+
+	// should be no errors
+	assert.Equal(b, flightOutput.ErrorInformation, "")
+	assert.Equal(b, solution, flightOutput.CalculateResult)
+	assert.Equal(b, path, flightOutput.Path)
+}
@@ -0,0 +1,72 @@
+package models
+
+// Itinerary is one separate trip found by FindItineraries
+type Itinerary struct {
+	CalculateResult       []string
+	FinalDepartureAirport string
+	FinalArrivalAirport   string
+	Path                  string
+	// LegIndexes are the positions in the input of this itinerary's flights, in travel order
+	LegIndexes []int
+	// Legs echoes the itinerary's legs in travel order, see FlightOutput.OrderedLegs
+	Legs Legs `json:",omitempty"`
+	// Distance is only filled in when asked for, see FlightsInput.Distance
+	Distance *TripDistance `json:",omitempty"`
+	// Timing is the block time of every flight and the elapsed trip time, see Legs.Timing
+	Timing *TripTiming `json:",omitempty"`
+}
+
+// FindItineraries splits the flights into every separate chain instead of
+// failing when they don't all connect, so one record dump holding two
+// unrelated trips gives back two itineraries ordered by their first flight
+// in the input. The top level fields of the output are only filled in when
+// there's exactly one itinerary. Duplicate airports and loops are still
+// errors since there's no telling which trip those flights belong to.
+func (fi FlightsInput) FindItineraries() (fo FlightOutput, err error) {
+	// validate our FlightsInput struct
+	err = validateFlightsInput(fi)
+	if err != nil {
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+	// no flights isn't zero itineraries, there's nothing to split
+	if len(fi) == 0 {
+		err = &EmptyInputError{}
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+
+	segments := findSegments(fi)
+	for _, segment := range segments {
+		if segment.Loop() {
+			err = &LoopError{Airports: segment.Airports, Indexes: segment.Indexes}
+			fo.ErrorInformation = err.Error()
+			return fo, err
+		}
+	}
+	return itinerariesOutput(segments), nil
+}
+
+// itinerariesOutput makes an Itinerary of every segment, filling in the top
+// level fields too when there's only one
+func itinerariesOutput(segments []Segment) (fo FlightOutput) {
+	for _, segment := range segments {
+		fo.Itineraries = append(fo.Itineraries, Itinerary{
+			CalculateResult:       []string{segment.Departure, segment.Arrival},
+			FinalDepartureAirport: segment.Departure,
+			FinalArrivalAirport:   segment.Arrival,
+			Path:                  concatPath(segment.Airports),
+			LegIndexes:            segment.Indexes,
+		})
+	}
+
+	if len(fo.Itineraries) == 1 {
+		itinerary := fo.Itineraries[0]
+		fo.CalculateResult = itinerary.CalculateResult
+		fo.FinalDepartureAirport = itinerary.FinalDepartureAirport
+		fo.FinalArrivalAirport = itinerary.FinalArrivalAirport
+		fo.Path = itinerary.Path
+		fo.LegOrder = itinerary.LegIndexes
+	}
+	return fo
+}
@@ -0,0 +1,119 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItinerariesTwoTrips(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// SFO -> ATL -> IND is an outbound trip, SLC -> JFK -> BOS an unrelated later one
+	inputData := `[
+  ["SLC", "JFK"], 
+  ["ATL", "IND"], 
+  ["SFO", "ATL"], 
+  ["JFK", "BOS"]
+  ]`
+	fi := models.FlightsInput{}
+
+	err := json.Unmarshal([]byte(inputData), &fi)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := fi.FindItineraries()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Itinerary{
+		{
+			CalculateResult:       []string{"SLC", "BOS"},
+			FinalDepartureAirport: "SLC",
+			FinalArrivalAirport:   "BOS",
+			Path:                  "SLC - JFK - BOS",
+			LegIndexes:            []int{0, 3},
+		},
+		{
+			CalculateResult:       []string{"SFO", "IND"},
+			FinalDepartureAirport: "SFO",
+			FinalArrivalAirport:   "IND",
+			Path:                  "SFO - ATL - IND",
+			LegIndexes:            []int{2, 1},
+		},
+	}, flightOutput.Itineraries)
+	// top level fields are ambiguous with more than one itinerary
+	assert.Empty(t, flightOutput.CalculateResult)
+	assert.Empty(t, flightOutput.Path)
+}
+
+func TestItinerariesSingleTrip(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO", "IND"}, {"ATL", "GSO"}}
+
+	flightOutput, err := fi.FindItineraries()
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Len(t, flightOutput.Itineraries, 1)
+	assert.Equal(t, []string{"SFO", "EWR"}, flightOutput.CalculateResult)
+	assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path)
+}
+
+func TestItinerariesLoop(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// JFK -> SLC -> JFK can't be split off into its own trip
+	fi := models.FlightsInput{{"SFO", "ATL"}, {"JFK", "SLC"}, {"SLC", "JFK"}}
+
+	_, err := fi.FindItineraries()
+	assert.True(t, errors.Is(err, models.ErrLoop))
+
+	loop := &models.LoopError{}
+	assert.True(t, errors.As(err, &loop))
+	assert.Equal(t, []int{1, 2}, loop.Indexes)
+}
+
+func TestItinerariesTimed(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	parse := func(body string) models.Legs {
+		legs := models.Legs{}
+		err := json.Unmarshal([]byte(body), &legs)
+		assert.Nil(t, err)
+		return legs
+	}
+
+	// by airport SFO - ORD - SFO is a loop, in time order it's a round trip
+	legs := parse(`[
+  {"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00-05:00"},
+  {"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00"}
+  ]`)
+	flightOutput, err := legs.FindItineraries()
+	assert.Nil(t, err)
+	assert.Len(t, flightOutput.Itineraries, 1)
+	assert.Equal(t, "SFO - ORD - SFO", flightOutput.Path)
+	assert.Equal(t, []int{1, 0}, flightOutput.LegOrder)
+	assert.Nil(t, legs.ValidateItineraries())
+
+	// a bad time doesn't split legs that chain by airport, as with Solve
+	legs = parse(`[
+  {"from": "A", "to": "B", "departure": "2026-10-01T10:00:00Z"},
+  {"from": "B", "to": "C", "departure": "2026-10-01T09:00:00Z"}
+  ]`)
+	flightOutput, err = legs.FindItineraries()
+	assert.Nil(t, err)
+	assert.Len(t, flightOutput.Itineraries, 1)
+	assert.Equal(t, "A - B - C", flightOutput.Path)
+
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Duration is a time.Duration that reads and writes JSON as a string like "1h30m0s"
+type Duration time.Duration
+
+// MarshalJSON writes d as time.Duration.String does
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON reads anything time.ParseDuration accepts, like "45m"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	s := ""
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("duration must be a string like \"45m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ConnectionRules decide when a layover is too short to make
+// and when it's long enough to count as a stopover
+type ConnectionRules struct {
+	// MinimumConnection applies at every airport without its own entry in Airports
+	MinimumConnection Duration `json:"minimumConnection"`
+	// Airports overrides MinimumConnection by airport code
+	Airports map[string]Duration `json:"airports,omitempty"`
+	// StopoverThreshold marks layovers at least this long as stopovers, zero turns stopovers off
+	StopoverThreshold Duration `json:"stopoverThreshold"`
+}
+
+// DefaultConnectionRules is a 45 minute minimum connection everywhere
+// with layovers of a day or more counted as stopovers
+func DefaultConnectionRules() ConnectionRules {
+	return ConnectionRules{
+		MinimumConnection: Duration(45 * time.Minute),
+		StopoverThreshold: Duration(24 * time.Hour),
+	}
+}
+
+// LoadConnectionRules reads ConnectionRules from a JSON file like
+// {"minimumConnection": "45m", "stopoverThreshold": "24h", "airports": {"ATL": "55m", "JFK": "1h15m"}},
+// anything the file leaves out keeps its DefaultConnectionRules value
+func LoadConnectionRules(path string) (ConnectionRules, error) {
+	rules := DefaultConnectionRules()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return rules, fmt.Errorf("unable to parse connection rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// MinimumConnectionAt returns the minimum connection time for airport
+func (cr ConnectionRules) MinimumConnectionAt(airport string) time.Duration {
+	mct, ok := cr.Airports[airport]
+	if ok {
+		return time.Duration(mct)
+	}
+	return time.Duration(cr.MinimumConnection)
+}
+
+// Layover is the time spent at an airport between two legs
+type Layover struct {
+	Airport string
+	// ArrivingLegIndex and DepartingLegIndex are the positions in the input
+	// of the leg landing at Airport and the one leaving it
+	ArrivingLegIndex  int
+	DepartingLegIndex int
+	Duration          Duration
+	MinimumConnection Duration
+	// BelowMinimumConnection is set when Duration is shorter than MinimumConnection
+	BelowMinimumConnection bool
+	// Stopover is set when Duration is at least the rules' StopoverThreshold
+	Stopover bool
+}
+
+// Layovers returns the layover between every two consecutive legs in order,
+// order being the LegOrder of a solved FlightOutput. Layovers where the
+// first leg has no arrival time or the second no departure time are skipped.
+func (ls Legs) Layovers(order []int, rules ConnectionRules) (layovers []Layover) {
+	for n := 1; n < len(order); n++ {
+		arriving := ls[order[n-1]]
+		departing := ls[order[n]]
+		if arriving.Arrival == nil || departing.Departure == nil {
+			continue
+		}
+
+		duration := departing.Departure.Sub(*arriving.Arrival)
+		mct := rules.MinimumConnectionAt(departing.From)
+		layovers = append(layovers, Layover{
+			Airport:                departing.From,
+			ArrivingLegIndex:       order[n-1],
+			DepartingLegIndex:      order[n],
+			Duration:               Duration(duration),
+			MinimumConnection:      Duration(mct),
+			BelowMinimumConnection: duration < mct,
+			Stopover:               rules.StopoverThreshold > 0 && duration >= time.Duration(rules.StopoverThreshold),
+		})
+	}
+	return layovers
+}
@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayovers(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// 30 minutes in ATL, 2 days in IND, and no arrival time for the last connection
+	sample := `[
+  {"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00:00Z", "arrival": "2026-10-01T13:00:00Z"},
+  {"from": "ATL", "to": "IND", "departure": "2026-10-01T13:30:00Z", "arrival": "2026-10-01T15:00:00Z"},
+  {"from": "IND", "to": "EWR", "departure": "2026-10-03T15:00:00Z"},
+  {"from": "EWR", "to": "BOS", "departure": "2026-10-03T20:00:00Z"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	rules := models.DefaultConnectionRules()
+	rules.Airports = map[string]models.Duration{"IND": models.Duration(3 * time.Hour)}
+
+	layovers := legs.Layovers([]int{0, 1, 2, 3}, rules)
+	assert.Equal(t, []models.Layover{
+		{
+			Airport:                "ATL",
+			ArrivingLegIndex:       0,
+			DepartingLegIndex:      1,
+			Duration:               models.Duration(30 * time.Minute),
+			MinimumConnection:      models.Duration(45 * time.Minute),
+			BelowMinimumConnection: true,
+		},
+		{
+			Airport:           "IND",
+			ArrivingLegIndex:  1,
+			DepartingLegIndex: 2,
+			Duration:          models.Duration(48 * time.Hour),
+			MinimumConnection: models.Duration(3 * time.Hour),
+			Stopover:          true,
+		},
+	}, layovers)
+}
+
+func TestLoadConnectionRules(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	err := os.WriteFile(path, []byte(`{"minimumConnection": "1h", "airports": {"ATL": "55m"}}`), 0o600)
+	assert.Nil(t, err)
+
+	rules, err := models.LoadConnectionRules(path)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour, rules.MinimumConnectionAt("SFO"))
+	assert.Equal(t, 55*time.Minute, rules.MinimumConnectionAt("ATL"))
+	// the file didn't say, so the default is kept
+	assert.Equal(t, models.Duration(24*time.Hour), rules.StopoverThreshold)
+
+	err = os.WriteFile(path, []byte(`{"minimumConnection": "soon"}`), 0o600)
+	assert.Nil(t, err)
+	_, err = models.LoadConnectionRules(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,475 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Leg is a single flight. In JSON it can be the legacy ["SFO", "ATL"] pair or
+// an object carrying departure and arrival times and where the record came from.
+// Times are RFC 3339, or local to the airport when given without an offset,
+// see Legs.ResolveTimezones:
+// {"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00:00-07:00", "arrival": "2026-10-01T16:05:00-04:00",
+// "flight": "DL1234", "carrier": "DL", "passenger": "P123", "source": "agencyA"}
+type Leg struct {
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	Departure *time.Time `json:"departure,omitempty"`
+	Arrival   *time.Time `json:"arrival,omitempty"`
+	// Flight is the flight number, e.g. DL1234
+	Flight string `json:"flight,omitempty"`
+	// Carrier is the operating airline's code, e.g. DL
+	Carrier   string `json:"carrier,omitempty"`
+	Passenger string `json:"passenger,omitempty"`
+	// Source is the carrier or agency group the record came from
+	Source string `json:"source,omitempty"`
+
+	// pair is a legacy pair exactly as it was given,
+	// kept so a malformed one is still reported by the solvers
+	pair []string
+	// departureLocal and arrivalLocal are set when the time was given without
+	// an offset, it's held as UTC until ResolveTimezones puts it in the airport's zone
+	departureLocal bool
+	arrivalLocal   bool
+}
+
+// localTimeLayouts are the accepted forms of a time given without an offset
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// parseLegTime reads an RFC 3339 time, or failing that a local one
+func parseLegTime(field, value string) (t time.Time, local bool, err error) {
+	t, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, false, nil
+	}
+	for _, layout := range localTimeLayouts {
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, true, nil
+		}
+	}
+	return t, false, fmt.Errorf("%s %q must be an RFC 3339 time like 2006-01-02T15:04:05-07:00, or a local time like 2006-01-02T15:04:05", field, value)
+}
+
+// LegTypeError is returned for a leg that's neither a pair of airport codes
+// nor an object, Err has the offset of the offending value
+type LegTypeError struct {
+	Err *json.UnmarshalTypeError
+}
+
+func (e *LegTypeError) Error() string {
+	return fmt.Sprintf("leg must be a [from, to] pair of airport codes or an object (found %s)", e.Err.Value)
+}
+
+// Unwrap returns the underlying JSON error
+func (e *LegTypeError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalJSON accepts either the legacy pair or the object form of a Leg
+func (l *Leg) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '[' && data[0] != '{' && !bytes.Equal(data, []byte("null")) {
+		token, _ := json.NewDecoder(bytes.NewReader(data)).Token()
+		return &LegTypeError{Err: &json.UnmarshalTypeError{Value: jsonKind(token), Type: reflect.TypeOf(Leg{}), Offset: int64(len(data))}}
+	}
+	if len(data) > 0 && data[0] == '[' {
+		pair := []string{}
+		err := json.Unmarshal(data, &pair)
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return &LegTypeError{Err: typeErr}
+		}
+		if err != nil {
+			return err
+		}
+		*l = Leg{pair: pair}
+		if len(pair) == 2 {
+			l.From = pair[0]
+			l.To = pair[1]
+		}
+		return nil
+	}
+
+	// legObject has the same fields as Leg without its UnmarshalJSON method,
+	// the times are read as strings so ones without an offset can be parsed too
+	type legObject Leg
+	obj := struct {
+		legObject
+		Departure *string `json:"departure"`
+		Arrival   *string `json:"arrival"`
+	}{}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+	*l = Leg(obj.legObject)
+
+	if obj.Departure != nil {
+		departure, local, err := parseLegTime("departure", *obj.Departure)
+		if err != nil {
+			return err
+		}
+		l.Departure = &departure
+		l.departureLocal = local
+	}
+	if obj.Arrival != nil {
+		arrival, local, err := parseLegTime("arrival", *obj.Arrival)
+		if err != nil {
+			return err
+		}
+		l.Arrival = &arrival
+		l.arrivalLocal = local
+	}
+	return nil
+}
+
+// MarshalJSON writes the leg back the way it was given, a legacy pair
+// as a pair and a time given without an offset still without one
+func (l Leg) MarshalJSON() ([]byte, error) {
+	if l.pair != nil {
+		return json.Marshal(l.pair)
+	}
+
+	// the same fields as Leg in the same order, with the times as strings
+	obj := struct {
+		From      string  `json:"from"`
+		To        string  `json:"to"`
+		Departure *string `json:"departure,omitempty"`
+		Arrival   *string `json:"arrival,omitempty"`
+		Flight    string  `json:"flight,omitempty"`
+		Carrier   string  `json:"carrier,omitempty"`
+		Passenger string  `json:"passenger,omitempty"`
+		Source    string  `json:"source,omitempty"`
+	}{
+		From:      l.From,
+		To:        l.To,
+		Flight:    l.Flight,
+		Carrier:   l.Carrier,
+		Passenger: l.Passenger,
+		Source:    l.Source,
+	}
+	if l.Departure != nil {
+		departure := formatLegTime(*l.Departure, l.departureLocal)
+		obj.Departure = &departure
+	}
+	if l.Arrival != nil {
+		arrival := formatLegTime(*l.Arrival, l.arrivalLocal)
+		obj.Arrival = &arrival
+	}
+	return json.Marshal(obj)
+}
+
+// formatLegTime is the opposite of parseLegTime
+func formatLegTime(t time.Time, local bool) string {
+	if local {
+		return t.Format(localTimeLayouts[0])
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// Pair returns the leg as a FlightsInput item, a malformed legacy
+// pair or an object missing an airport comes back malformed too
+func (l Leg) Pair() []string {
+	if l.pair != nil {
+		return l.pair
+	}
+	pair := []string{}
+	for _, airport := range []string{l.From, l.To} {
+		if airport != "" {
+			pair = append(pair, airport)
+		}
+	}
+	return pair
+}
+
+// Legs is a request's flights in the order they were given
+type Legs []Leg
+
+// UnmarshalJSON decodes a list of legs one at a time with a LegDecoder so
+// errors inside a leg point at their offset in the whole list, not just in that leg
+func (ls *Legs) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*ls = nil
+		return nil
+	}
+
+	ld := NewLegDecoder(bytes.NewReader(data))
+	legs := Legs{}
+	for {
+		leg, err := ld.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		decodeErr := &LegDecodeError{}
+		if errors.As(err, &decodeErr) {
+			return decodeErr.Err
+		}
+		if err != nil {
+			return err
+		}
+		legs = append(legs, leg)
+	}
+	*ls = legs
+	return nil
+}
+
+// jsonKind names the kind of JSON value a token starts, for error messages
+func jsonKind(token json.Token) string {
+	switch token.(type) {
+	case json.Delim:
+		return "object"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprint(token)
+}
+
+// FlightsInput drops everything but the airports so the legs can be given to a PathSolver
+func (ls Legs) FlightsInput() FlightsInput {
+	fi := make(FlightsInput, len(ls))
+	for i, leg := range ls {
+		fi[i] = leg.Pair()
+	}
+	return fi
+}
+
+// hasObjects reports whether any leg was given in the object form,
+// requests made up of only legacy pairs get the legacy response
+func (ls Legs) hasObjects() bool {
+	for _, leg := range ls {
+		if leg.pair == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ordered returns the legs at indexes in that order
+func (ls Legs) ordered(indexes []int) Legs {
+	legs := make(Legs, 0, len(indexes))
+	for _, i := range indexes {
+		legs = append(legs, ls[i])
+	}
+	return legs
+}
+
+// timed reports whether every leg has a departure time to sort by
+func (ls Legs) timed() bool {
+	for _, leg := range ls {
+		if leg.Departure == nil {
+			return false
+		}
+	}
+	return len(ls) > 0
+}
+
+// Solve orders the legs and returns the resulting FlightOutput. When every
+// leg has a departure time the legs are ordered chronologically, otherwise
+// solver orders them by airport and any times given are checked against
+// that order. Timed legs that don't chain in time order but do by airport
+// are ordered by solver too, a bad time is a conflict rather than a reason
+// to fail. Either way TimingConflicts lists every leg whose times
+// contradict the order it ended up in.
+func (ls Legs) Solve(solver PathSolver) (fo FlightOutput, err error) {
+	if ls.timed() {
+		fo, err = ls.solveChronologically()
+		if errors.Is(err, ErrDisconnectedSegments) {
+			byAirport, airportErr := solver.Solve(ls.FlightsInput())
+			if airportErr == nil {
+				fo, err = byAirport, nil
+			}
+		}
+	} else {
+		fo, err = solver.Solve(ls.FlightsInput())
+	}
+	if err != nil {
+		return fo, err
+	}
+	ls.describe(&fo)
+	return fo, nil
+}
+
+// describe fills in what the legs' times and objects add to a solved fo
+func (ls Legs) describe(fo *FlightOutput) {
+	fo.TimingConflicts = ls.timingConflicts(fo.LegOrder)
+	if ls.hasObjects() && len(fo.LegOrder) > 0 {
+		fo.OrderedLegs = ls.ordered(fo.LegOrder)
+	}
+}
+
+// FindItineraries is FlightsInput.FindItineraries for legs, every
+// itinerary also echoes its legs when any were given as objects. When every
+// leg has a departure time they're split in time order the way Solve orders
+// them, so a round trip is one itinerary rather than a loop, unless the
+// airports make one chain that the times break up.
+func (ls Legs) FindItineraries() (fo FlightOutput, err error) {
+	if ls.timed() {
+		fo, err = ls.itinerariesChronologically()
+		if err == nil && len(fo.Itineraries) > 1 {
+			byAirport, airportErr := ls.FlightsInput().FindItineraries()
+			if airportErr == nil && len(byAirport.Itineraries) == 1 {
+				fo = byAirport
+			}
+		}
+	} else {
+		fo, err = ls.FlightsInput().FindItineraries()
+	}
+	if err != nil {
+		return fo, err
+	}
+
+	if ls.hasObjects() {
+		for i, itinerary := range fo.Itineraries {
+			fo.Itineraries[i].Legs = ls.ordered(itinerary.LegIndexes)
+		}
+		if len(fo.Itineraries) == 1 {
+			fo.OrderedLegs = fo.Itineraries[0].Legs
+		}
+	}
+	return fo, nil
+}
+
+// solveChronologically orders the legs by departure time and makes sure
+// each one leaves from where the one before it landed
+func (ls Legs) solveChronologically() (fo FlightOutput, err error) {
+	order, segments, err := ls.chronologicalSegments()
+	if err != nil {
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+	if len(segments) > 1 {
+		err = &DisconnectedSegmentsError{Segments: segments}
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+
+	path := segments[0].Airports
+	fo.FinalDepartureAirport = path[0]
+	fo.FinalArrivalAirport = path[len(path)-1]
+	fo.CalculateResult = []string{
+		fo.FinalDepartureAirport,
+		fo.FinalArrivalAirport,
+	}
+	fo.Path = concatPath(path)
+	fo.LegOrder = order
+
+	return fo, nil
+}
+
+// itinerariesChronologically splits the legs in departure time order
+// wherever one doesn't leave from where the one before it landed
+func (ls Legs) itinerariesChronologically() (fo FlightOutput, err error) {
+	_, segments, err := ls.chronologicalSegments()
+	if err != nil {
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+	return itinerariesOutput(segments), nil
+}
+
+// chronologicalSegments orders the legs by departure time and splits them
+// into segments wherever a leg doesn't leave from where the last one landed
+func (ls Legs) chronologicalSegments() (order []int, segments []Segment, err error) {
+	for i, flightPair := range ls.FlightsInput() {
+		// ensure all flightPairs are exactly 2 long
+		if len(flightPair) != 2 {
+			return nil, nil, &MalformedLegError{Index: i, Leg: flightPair}
+		}
+	}
+
+	// ties keep the order the legs were given in
+	order = make([]int, len(ls))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ls[order[a]].Departure.Before(*ls[order[b]].Departure)
+	})
+
+	// split wherever a leg doesn't leave from where the last one landed
+	for _, i := range order {
+		leg := ls[i]
+		if len(segments) == 0 || segments[len(segments)-1].Arrival != leg.From {
+			segments = append(segments, Segment{
+				Departure: leg.From,
+				Airports:  []string{leg.From},
+			})
+		}
+		segment := &segments[len(segments)-1]
+		segment.Arrival = leg.To
+		segment.Airports = append(segment.Airports, leg.To)
+		segment.Indexes = append(segment.Indexes, i)
+	}
+	return order, segments, nil
+}
+
+// kinds of TimingConflict
+const (
+	// a leg lands before it takes off
+	ArrivesBeforeDeparture = "arrives-before-departure"
+	// a leg takes off before the leg before it lands
+	DepartsBeforePreviousArrival = "departs-before-previous-arrival"
+	// a leg takes off before the leg before it takes off
+	DepartsBeforePreviousDeparture = "departs-before-previous-departure"
+)
+
+// TimingConflict is a leg whose times contradict the order it was put in
+type TimingConflict struct {
+	Kind string
+	// LegIndex is the position in the input of the conflicting leg
+	LegIndex int
+	// PreviousLegIndex is the position in the input of the leg before it in
+	// the path, or -1 when the conflict is within the one leg
+	PreviousLegIndex int
+	Detail           string
+}
+
+// timingConflicts checks the times of the legs in the given travel order,
+// legs missing the times a check needs are skipped by that check
+func (ls Legs) timingConflicts(order []int) (conflicts []TimingConflict) {
+	for n, i := range order {
+		leg := ls[i]
+		if leg.Departure != nil && leg.Arrival != nil && leg.Arrival.Before(*leg.Departure) {
+			conflicts = append(conflicts, TimingConflict{
+				Kind:             ArrivesBeforeDeparture,
+				LegIndex:         i,
+				PreviousLegIndex: -1,
+				Detail:           fmt.Sprintf("%s - %s arrives at %s before it departs at %s.", leg.From, leg.To, leg.Arrival.Format(time.RFC3339), leg.Departure.Format(time.RFC3339)),
+			})
+		}
+
+		if n == 0 || leg.Departure == nil {
+			continue
+		}
+		prevIndex := order[n-1]
+		prev := ls[prevIndex]
+		switch {
+		case prev.Arrival != nil && leg.Departure.Before(*prev.Arrival):
+			conflicts = append(conflicts, TimingConflict{
+				Kind:             DepartsBeforePreviousArrival,
+				LegIndex:         i,
+				PreviousLegIndex: prevIndex,
+				Detail:           fmt.Sprintf("%s - %s departs at %s before %s - %s arrives at %s.", leg.From, leg.To, leg.Departure.Format(time.RFC3339), prev.From, prev.To, prev.Arrival.Format(time.RFC3339)),
+			})
+		case prev.Arrival == nil && prev.Departure != nil && leg.Departure.Before(*prev.Departure):
+			conflicts = append(conflicts, TimingConflict{
+				Kind:             DepartsBeforePreviousDeparture,
+				LegIndex:         i,
+				PreviousLegIndex: prevIndex,
+				Detail:           fmt.Sprintf("%s - %s departs at %s before %s - %s departs at %s.", leg.From, leg.To, leg.Departure.Format(time.RFC3339), prev.From, prev.To, prev.Departure.Format(time.RFC3339)),
+			})
+		}
+	}
+	return conflicts
+}
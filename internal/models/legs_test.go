@@ -0,0 +1,268 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegsDeserializeMixedInput(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sample := `[["IND", "EWR"], {"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00:00-07:00", "arrival": "2026-10-01T16:05:00-04:00"}, ["GSO"]]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+	assert.Len(t, legs, 3)
+
+	assert.Equal(t, "IND", legs[0].From)
+	assert.Equal(t, "EWR", legs[0].To)
+	assert.Nil(t, legs[0].Departure)
+
+	assert.Equal(t, "SFO", legs[1].From)
+	assert.Equal(t, "2026-10-01T15:00:00Z", legs[1].Departure.UTC().Format("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, "2026-10-01T20:05:00Z", legs[1].Arrival.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	// malformed legacy pairs are kept as they were given
+	assert.Equal(t, models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO"}}, legs.FlightsInput())
+}
+
+func TestLegsDeserializeTypeErrorOffset(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sample := `[["IND", "EWR"], ["SFO", 7]]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	typeErr := &json.UnmarshalTypeError{}
+	assert.True(t, errors.As(err, &typeErr))
+	// offset is just past the 7, in the whole list rather than in the leg
+	assert.Equal(t, int64(26), typeErr.Offset)
+}
+
+func TestLegsDeserializeNotALeg(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	for _, tc := range []struct {
+		sample string
+		offset int64
+	}{
+		{sample: `[["IND", "EWR"], 7]`, offset: 18},
+		{sample: `[["IND", "EWR"], "SFO"]`, offset: 22},
+		{sample: `[[1]]`, offset: 3},
+	} {
+		legs := models.Legs{}
+		err := json.Unmarshal([]byte(tc.sample), &legs)
+
+		legErr := &models.LegTypeError{}
+		assert.True(t, errors.As(err, &legErr), tc.sample)
+		assert.NotContains(t, err.Error(), "struct", tc.sample)
+		// still an offset into the whole list
+		typeErr := &json.UnmarshalTypeError{}
+		assert.True(t, errors.As(err, &typeErr), tc.sample)
+		assert.Equal(t, tc.offset, typeErr.Offset, tc.sample)
+	}
+}
+
+func TestLegsSolveChronologically(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// a round trip through ORD that the airport solvers would call a loop
+	sample := `[
+  {"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00-05:00", "arrival": "2026-10-03T20:30:00-07:00"},
+  {"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00", "arrival": "2026-10-01T14:10:00-05:00"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SFO", "SFO"}, flightOutput.CalculateResult)
+	assert.Equal(t, "SFO - ORD - SFO", flightOutput.Path)
+	assert.Equal(t, []int{1, 0}, flightOutput.LegOrder)
+	assert.Empty(t, flightOutput.TimingConflicts)
+}
+
+func TestLegsSolveChronologicallyGap(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// nothing gets the passenger from ORD to JFK
+	sample := `[
+  {"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00-07:00"},
+  {"from": "JFK", "to": "BOS", "departure": "2026-10-02T08:00:00-04:00"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	_, err = legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	disconnected := &models.DisconnectedSegmentsError{}
+	assert.True(t, errors.As(err, &disconnected))
+	assert.Len(t, disconnected.Segments, 2)
+	assert.Equal(t, []int{1}, disconnected.Segments[1].Indexes)
+}
+
+func TestLegsSolveBadTime(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// in time order C is reached before A is left, but the airports chain fine
+	sample := `[
+  {"from": "A", "to": "B", "departure": "2026-10-01T10:00:00Z"},
+  {"from": "B", "to": "C", "departure": "2026-10-01T09:00:00Z"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	solver := models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap)
+	flightOutput, err := legs.Solve(solver)
+	// the bad time is flagged rather than failing
+	assert.Nil(t, err)
+	assert.Equal(t, "A - B - C", flightOutput.Path)
+	assert.Equal(t, []int{0, 1}, flightOutput.LegOrder)
+	assert.Equal(t, []models.TimingConflict{{
+		Kind:             models.DepartsBeforePreviousDeparture,
+		LegIndex:         1,
+		PreviousLegIndex: 0,
+		Detail:           "B - C departs at 2026-10-01T09:00:00Z before A - B departs at 2026-10-01T10:00:00Z.",
+	}}, flightOutput.TimingConflicts)
+	assert.Nil(t, legs.Validate(solver))
+
+	// legs that chain neither way are still disconnected
+	legs = models.Legs{}
+	err = json.Unmarshal([]byte(`[
+  {"from": "A", "to": "B", "departure": "2026-10-01T10:00:00Z"},
+  {"from": "C", "to": "D", "departure": "2026-10-01T09:00:00Z"}
+  ]`), &legs)
+	assert.Nil(t, err)
+	_, err = legs.Solve(solver)
+	assert.True(t, errors.Is(err, models.ErrDisconnectedSegments))
+	assert.True(t, errors.Is(legs.Validate(solver), models.ErrDisconnectedSegments))
+}
+
+func TestLegsTimingConflicts(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the first leg is missing its departure time so the airports decide the order,
+	// ATL -> GSO lands before it takes off and GSO -> IND leaves before SFO -> ATL lands
+	sample := `[
+  {"from": "GSO", "to": "IND", "departure": "2026-10-01T12:00:00Z"},
+  {"from": "SFO", "to": "ATL", "arrival": "2026-10-01T13:00:00Z"},
+  {"from": "ATL", "to": "GSO", "departure": "2026-10-01T14:00:00Z", "arrival": "2026-10-01T13:30:00Z"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	// should be no errors, conflicts are only flagged
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ATL - GSO - IND", flightOutput.Path)
+	assert.Len(t, flightOutput.TimingConflicts, 2)
+
+	assert.Equal(t, models.ArrivesBeforeDeparture, flightOutput.TimingConflicts[0].Kind)
+	assert.Equal(t, 2, flightOutput.TimingConflicts[0].LegIndex)
+	assert.Equal(t, -1, flightOutput.TimingConflicts[0].PreviousLegIndex)
+
+	assert.Equal(t, models.DepartsBeforePreviousArrival, flightOutput.TimingConflicts[1].Kind)
+	assert.Equal(t, 0, flightOutput.TimingConflicts[1].LegIndex)
+	assert.Equal(t, 2, flightOutput.TimingConflicts[1].PreviousLegIndex)
+}
+
+func TestLegsEchoMetadata(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sample := `[
+  {"from": "ATL", "to": "EWR", "flight": "DL2345", "carrier": "DL", "passenger": "P123", "source": "agencyB"},
+  {"from": "SFO", "to": "ATL", "flight": "DL1234", "carrier": "DL", "passenger": "P123", "source": "agencyA"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ATL - EWR", flightOutput.Path)
+	assert.Equal(t, models.Legs{
+		{From: "SFO", To: "ATL", Flight: "DL1234", Carrier: "DL", Passenger: "P123", Source: "agencyA"},
+		{From: "ATL", To: "EWR", Flight: "DL2345", Carrier: "DL", Passenger: "P123", Source: "agencyB"},
+	}, flightOutput.OrderedLegs)
+}
+
+func TestLegsLegacyPairsNotEchoed(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(`[["ATL", "EWR"], ["SFO", "ATL"]]`), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	flightOutput, err := legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	// should be no errors
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ATL - EWR", flightOutput.Path)
+	assert.Nil(t, flightOutput.OrderedLegs)
+
+	out, err := json.Marshal(flightOutput)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(out), "OrderedLegs")
+}
+
+func TestLegsRoundTrip(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// pairs stay pairs and local times stay local
+	sample := `[["IND","EWR"],{"from":"SFO","to":"IND","departure":"2026-10-01T08:00:00","arrival":"2026-10-01T16:05:00-04:00","flight":"UA1"}]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	out, err := json.Marshal(legs)
+	assert.Nil(t, err)
+	assert.Equal(t, sample, string(out))
+
+	again := models.Legs{}
+	err = json.Unmarshal(out, &again)
+	assert.Nil(t, err)
+	assert.Equal(t, legs, again)
+}
@@ -5,12 +5,18 @@ import (
 	"fmt"
 )
 
-func (fi FlightsInput) FindStartAndEndFlightLinkedList() (fo FlightOutput) {
+func (fi FlightsInput) FindStartAndEndFlightLinkedList() (fo FlightOutput, err error) {
 	// validate our FlightsInput struct
-	err := validateFlightsInput(fi)
+	err = validateFlightsInput(fi)
 	if err != nil {
 		fo.ErrorInformation = err.Error()
-		return fo
+		return fo, err
+	}
+	// no flights leaves the list empty with nothing at its front
+	if len(fi) == 0 {
+		err = &EmptyInputError{}
+		fo.ErrorInformation = err.Error()
+		return fo, err
 	}
 
 	// using a map to keep track of which airport is in what location in the list
@@ -38,15 +44,24 @@ func (fi FlightsInput) FindStartAndEndFlightLinkedList() (fo FlightOutput) {
 	// this means our notFound slice was unable to empty completely
 	// indicating there's some orphans remainging in the flight path
 	if !solutionFound {
-		fo.ErrorInformation = "Unable to find a connecting path for given flights."
-		return fo
+		err = &DisconnectedSegmentsError{Segments: findSegments(fi)}
+		fo.ErrorInformation = err.Error()
+		return fo, err
 	}
 
 	// check that the flight path is valid
 	valid := validFlightPath(newLL)
 	if !valid {
-		fo.ErrorInformation = "Duplicates found in flight path. There's a complete or partial loop in given flight plan, or duplicate arrival/departure airports."
-		return fo
+		// the list doesn't know which flights made the loop, so find them
+		err = &LoopError{}
+		for _, segment := range findSegments(fi) {
+			if segment.Loop() {
+				err = &LoopError{Airports: segment.Airports, Indexes: segment.Indexes}
+				break
+			}
+		}
+		fo.ErrorInformation = err.Error()
+		return fo, err
 	}
 
 	startFlight := newLL.Front().Value.(string)
@@ -62,36 +77,38 @@ func (fi FlightsInput) FindStartAndEndFlightLinkedList() (fo FlightOutput) {
 
 	path := concatLinkedList(newLL)
 	fo.Path = path
+	fo.LegOrder = legOrderFromLinkedList(newLL, fi)
 
-	return fo
+	return fo, nil
 }
 
 func validateFlightsInput(fi FlightsInput) error {
 	// ensure every FlightsInput has two items
 	// also ensure arrivals and departures are unique
-	arrivals := make(map[string]string)
-	departures := make(map[string]string)
-	for _, flightPair := range fi {
+	// airport -> index of the first flight using it
+	arrivals := make(map[string]int)
+	departures := make(map[string]int)
+	for i, flightPair := range fi {
 		// ensure all flightPairs are exactly 2 long
 		if len(flightPair) != 2 {
-			return fmt.Errorf("Item %v does not have exactly two airports.", flightPair)
+			return &MalformedLegError{Index: i, Leg: flightPair}
 		}
 
 		// ensure departure is unique
 		departure := flightPair[0]
-		_, ok := departures[departure]
+		first, ok := departures[departure]
 		if ok {
-			return fmt.Errorf("Departure airport %v appears more than once in the given flight plan.", departure)
+			return &DuplicateDepartureError{Airport: departure, Indexes: []int{first, i}}
 		}
-		departures[departure] = ""
+		departures[departure] = i
 
 		// ensure arrival is unique
 		arrival := flightPair[1]
-		_, ok = arrivals[arrival]
+		first, ok = arrivals[arrival]
 		if ok {
-			return fmt.Errorf("Arrival airport %v appears more than once in the given flight plan.", arrival)
+			return &DuplicateArrivalError{Airport: arrival, Indexes: []int{first, i}}
 		}
-		arrivals[arrival] = ""
+		arrivals[arrival] = i
 	}
 
 	return nil
@@ -109,6 +126,21 @@ func concatLinkedList(ll *list.List) (out string) {
 	return out
 }
 
+// find the position in fi of the flight leaving every airport in the list but the last,
+// departures are unique by the time the list is complete
+func legOrderFromLinkedList(ll *list.List, fi FlightsInput) []int {
+	departureIndex := make(map[string]int, len(fi))
+	for i, flightPair := range fi {
+		departureIndex[flightPair[0]] = i
+	}
+
+	order := make([]int, 0, len(fi))
+	for e := ll.Front(); e != nil && e.Next() != nil; e = e.Next() {
+		order = append(order, departureIndex[e.Value.(string)])
+	}
+	return order
+}
+
 // ensure an airport is only in the linked list once
 func validFlightPath(ll *list.List) bool {
 	foundItems := make(map[string]string)
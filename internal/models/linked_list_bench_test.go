@@ -31,12 +31,12 @@ func BenchmarkLinkedList10Flights(b *testing.B) {
 		b.Fail()
 	}
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, _ := fi.FindStartAndEndFlightLinkedList()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightLinkedList()
+		flightOutput, _ = fi.FindStartAndEndFlightLinkedList()
 	}
 
 	// should be no errors
@@ -48,12 +48,12 @@ func BenchmarkLinkedList10Flights(b *testing.B) {
 func BenchmarkLinkedList100Flights(b *testing.B) {
 	fi, solution, path := generateRandomFlightPath(1000)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, _ := fi.FindStartAndEndFlightLinkedList()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightLinkedList()
+		flightOutput, _ = fi.FindStartAndEndFlightLinkedList()
 	}
 
 	// should be no errors
@@ -65,12 +65,81 @@ func BenchmarkLinkedList100Flights(b *testing.B) {
 func BenchmarkLinkedList1000Flights(b *testing.B) {
 	fi, solution, path := generateRandomFlightPath(1000)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, _ := fi.FindStartAndEndFlightLinkedList()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightLinkedList()
+		flightOutput, _ = fi.FindStartAndEndFlightLinkedList()
+	}
+
+	// should be no errors
+	assert.Equal(b, flightOutput.ErrorInformation, "")
+	assert.Equal(b, flightOutput.CalculateResult, solution)
+	assert.Equal(b, flightOutput.Path, path)
+}
+
+func BenchmarkHashMap10Flights(b *testing.B) {
+	fi := models.FlightsInput{}
+
+	inputData := `[
+  ["IND", "EWR"], 
+  ["EWR", "AAA"],
+  ["AAA", "AAB"],
+  ["AAB", "AAC"],
+  ["AAC", "AAD"],
+  ["AAD", "AAE"],
+  ["AAE", "AAF"],
+  ["AAF", "AAG"],
+  ["AAG", "AAH"],
+  ["AAH", "AAI"]
+  ]`
+
+	err := json.Unmarshal([]byte(inputData), &fi)
+	if err != nil {
+		b.Fail()
+	}
+
+	flightOutput, _ := fi.FindStartAndEndFlightHashMap()
+
+	// reset timer since we ran expensive setup funcs
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		flightOutput, _ = fi.FindStartAndEndFlightHashMap()
+	}
+
+	// should be no errors
+	assert.Equal(b, flightOutput.ErrorInformation, "")
+	assert.Equal(b, flightOutput.CalculateResult, []string{"IND", "AAI"})
+	assert.Equal(b, "IND - EWR - AAA - AAB - AAC - AAD - AAE - AAF - AAG - AAH - AAI", flightOutput.Path)
+}
+
+func BenchmarkHashMap1000Flights(b *testing.B) {
+	fi, solution, path := generateRandomFlightPath(1000)
+
+	flightOutput, _ := fi.FindStartAndEndFlightHashMap()
+
+	// reset timer since we ran expensive setup funcs
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		flightOutput, _ = fi.FindStartAndEndFlightHashMap()
+	}
+
+	// should be no errors
+	assert.Equal(b, flightOutput.ErrorInformation, "")
+	assert.Equal(b, flightOutput.CalculateResult, solution)
+	assert.Equal(b, flightOutput.Path, path)
+}
+
+func BenchmarkHashMap100000Flights(b *testing.B) {
+	fi, solution, path := generateRandomFlightPath(100000)
+
+	flightOutput, _ := fi.FindStartAndEndFlightHashMap()
+
+	// reset timer since we ran expensive setup funcs
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		flightOutput, _ = fi.FindStartAndEndFlightHashMap()
 	}
 
 	// should be no errors
@@ -100,12 +169,12 @@ func BenchmarkNaiveImpl10Flights(b *testing.B) {
 		b.Fail()
 	}
 
-	flightOutput := fi.FindStartAndEndFlightNaive()
+	flightOutput, _ := fi.FindStartAndEndFlightNaive()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightNaive()
+		flightOutput, _ = fi.FindStartAndEndFlightNaive()
 	}
 
 	// should be no errors
@@ -118,12 +187,12 @@ func BenchmarkNaiveImpl10Flights(b *testing.B) {
 func BenchmarkNaive100Flights(b *testing.B) {
 	fi, solution, _ := generateRandomFlightPath(100)
 
-	flightOutput := fi.FindStartAndEndFlightNaive()
+	flightOutput, _ := fi.FindStartAndEndFlightNaive()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightNaive()
+		flightOutput, _ = fi.FindStartAndEndFlightNaive()
 	}
 
 	// should be no errors
@@ -133,12 +202,12 @@ func BenchmarkNaive100Flights(b *testing.B) {
 func BenchmarkNaive1000Flights(b *testing.B) {
 	fi, solution, _ := generateRandomFlightPath(1000)
 
-	flightOutput := fi.FindStartAndEndFlightNaive()
+	flightOutput, _ := fi.FindStartAndEndFlightNaive()
 
 	// reset timer since we ran expensive setup funcs
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		flightOutput = fi.FindStartAndEndFlightNaive()
+		flightOutput, _ = fi.FindStartAndEndFlightNaive()
 	}
 
 	// should be no errors
@@ -153,8 +222,8 @@ func generateRandomFlightPath(length int) (flights models.FlightsInput, solution
 	lastItem := "AAA"
 	firstItem := ""
 	orderedFlightSlice := []string{lastItem}
-	// keep track of values we've already seen
-	seen := make(map[string]string)
+	// keep track of values we've already seen, including the starting airport
+	seen := map[string]string{lastItem: ""}
 	for i := 0; i < length; i++ {
 		// attempt 100 times to generate a non-colliding airport like SFO"
 		for j := 0; j < 100; j++ {
@@ -25,7 +25,7 @@ func TestLinkedListComplex(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should be no errors
 	assert.Equal(t, "", flightOutput.ErrorInformation)
 	assert.Equal(t, []string{"SFO", "EWR"}, flightOutput.CalculateResult)
@@ -51,7 +51,7 @@ func TestLinkedListHuge(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should be no errors
 	assert.Equal(t, "", flightOutput.ErrorInformation)
 	// assert.Equal(t, flightOutput.RawOutput, []string{"SFO", "EWR"})
@@ -77,7 +77,7 @@ func TestLinkedListLoop(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should be no errors
 	assert.Contains(t, flightOutput.ErrorInformation, "Duplicates found in")
 }
@@ -101,7 +101,7 @@ func TestLinkedListPartialLoop(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should be no errors
 	assert.Contains(t, flightOutput.ErrorInformation, "Arrival airport ATL")
 }
@@ -125,7 +125,7 @@ func TestLinkedListUnconnectedPaths(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should be no errors
 	assert.Contains(t, flightOutput.ErrorInformation, "Unable to find a connecting path")
 }
@@ -149,7 +149,7 @@ func TestLinkedListDoubleArrivals(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should have an error
 	assert.Contains(t, flightOutput.ErrorInformation, "Departure airport ATL")
 }
@@ -173,7 +173,7 @@ func TestLinkedListDoubleDepartures(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should have an error
 	assert.Contains(t, flightOutput.ErrorInformation, "Arrival airport IND")
 }
@@ -196,7 +196,7 @@ func TestLinkedListInvalidList(t *testing.T) {
 	// ensure no Unmarshal error
 	assert.Nil(t, err)
 
-	flightOutput := fi.FindStartAndEndFlightLinkedList()
+	flightOutput, err := fi.FindStartAndEndFlightLinkedList()
 	// should have an error
 	assert.Contains(t, flightOutput.ErrorInformation, "Item [IND] does not have exactly two airports.")
 }
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// kinds of Location
+const (
+	// on the ground at Airport
+	AtAirport = "at-airport"
+	// flying From - To
+	InTheAir = "in-the-air"
+	// the legs don't say, usually because of a gap in the records
+	LocationUnknown = "unknown"
+)
+
+// Location is where the legs put a passenger at a moment in time
+type Location struct {
+	Kind string
+	At   time.Time
+	// Airport is where the passenger is when Kind is AtAirport
+	Airport string `json:",omitempty"`
+	// From and To are the airports of the leg being flown when Kind is InTheAir
+	From string `json:",omitempty"`
+	To   string `json:",omitempty"`
+	// PercentComplete is how much of the leg's block time has passed when Kind is InTheAir
+	PercentComplete float64 `json:",omitempty"`
+	// LegIndex is the position in the input of the leg being flown, or -1
+	LegIndex int
+	// PreviousLegIndex and NextLegIndex are the positions in the input of the
+	// last leg to land before At and the first to take off after it, or -1
+	PreviousLegIndex int
+	NextLegIndex     int
+	// Since and Until are when the passenger got to and leaves Airport, when the legs say
+	Since  *time.Time `json:",omitempty"`
+	Until  *time.Time `json:",omitempty"`
+	Detail string
+}
+
+// LocationAt works out where the legs put a passenger at the given time.
+// Only legs with both a departure and an arrival time are used. Between
+// one leg landing and the next taking off from the same airport the
+// passenger is at that airport, as they are before their first leg and
+// after their last. When the next leg leaves from somewhere other than
+// where the last one landed there's no telling where they were in between.
+func (ls Legs) LocationAt(at time.Time) Location {
+	location := Location{
+		Kind:             LocationUnknown,
+		At:               at,
+		LegIndex:         -1,
+		PreviousLegIndex: -1,
+		NextLegIndex:     -1,
+	}
+
+	order := []int{}
+	for i, leg := range ls {
+		if leg.Departure != nil && leg.Arrival != nil {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		location.Detail = "No legs have both a departure and an arrival time."
+		return location
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ls[order[a]].Departure.Before(*ls[order[b]].Departure)
+	})
+
+	for _, i := range order {
+		leg := ls[i]
+		switch {
+		case !at.Before(*leg.Departure) && at.Before(*leg.Arrival):
+			// in the air, legs that overlap are reported as timing conflicts
+			// by Solve so the earliest one to take off wins here
+			location.Kind = InTheAir
+			location.From, location.To = leg.From, leg.To
+			location.LegIndex = i
+			location.PercentComplete = 100 * float64(at.Sub(*leg.Departure)) / float64(leg.Arrival.Sub(*leg.Departure))
+			location.Since, location.Until = leg.Departure, leg.Arrival
+			location.Detail = fmt.Sprintf("In the air between %s and %s, %.0f%% complete.", leg.From, leg.To, location.PercentComplete)
+			return location
+		case !leg.Arrival.After(at):
+			if location.PreviousLegIndex == -1 || leg.Arrival.After(*ls[location.PreviousLegIndex].Arrival) {
+				location.PreviousLegIndex = i
+			}
+		case leg.Departure.After(at) && location.NextLegIndex == -1:
+			location.NextLegIndex = i
+		}
+	}
+
+	var prev, next *Leg
+	if location.PreviousLegIndex != -1 {
+		prev = &ls[location.PreviousLegIndex]
+	}
+	if location.NextLegIndex != -1 {
+		next = &ls[location.NextLegIndex]
+	}
+
+	switch {
+	case prev != nil && next != nil && prev.To != next.From:
+		location.Detail = fmt.Sprintf("Gap in records between landing at %s at %s and departing %s at %s.",
+			prev.To, prev.Arrival.Format(time.RFC3339), next.From, next.Departure.Format(time.RFC3339))
+		return location
+	case prev != nil:
+		location.Airport = prev.To
+		location.Since = prev.Arrival
+		if next != nil {
+			location.Until = next.Departure
+		}
+	default:
+		// before the first leg takes off
+		location.Airport = next.From
+		location.Until = next.Departure
+	}
+	location.Kind = AtAirport
+	location.Detail = fmt.Sprintf("At %s.", location.Airport)
+	return location
+}
@@ -0,0 +1,79 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegsLocationAt(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(`[
+  {"from": "ATL", "to": "GSO", "departure": "2026-10-01T18:00:00Z", "arrival": "2026-10-01T19:00:00Z"},
+  {"from": "SFO", "to": "ATL", "departure": "2026-10-01T10:00:00Z", "arrival": "2026-10-01T14:00:00Z"},
+  {"from": "IND", "to": "EWR", "departure": "2026-10-02T10:00:00Z", "arrival": "2026-10-02T12:00:00Z"},
+  ["GSO", "IND"]
+  ]`), &legs)
+	assert.Nil(t, err)
+
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		assert.Nil(t, err)
+		return parsed
+	}
+
+	// before the first leg
+	location := legs.LocationAt(at("2026-10-01T08:00:00Z"))
+	assert.Equal(t, models.AtAirport, location.Kind)
+	assert.Equal(t, "SFO", location.Airport)
+	assert.Nil(t, location.Since)
+	assert.Equal(t, at("2026-10-01T10:00:00Z"), *location.Until)
+	assert.Equal(t, 1, location.NextLegIndex)
+
+	// a quarter of the way from SFO to ATL
+	location = legs.LocationAt(at("2026-10-01T11:00:00Z"))
+	assert.Equal(t, models.InTheAir, location.Kind)
+	assert.Equal(t, "SFO", location.From)
+	assert.Equal(t, "ATL", location.To)
+	assert.Equal(t, 1, location.LegIndex)
+	assert.InDelta(t, 25, location.PercentComplete, 0.001)
+	assert.Equal(t, "In the air between SFO and ATL, 25% complete.", location.Detail)
+
+	// between connecting legs, landing counts as being on the ground
+	for _, value := range []string{"2026-10-01T14:00:00Z", "2026-10-01T16:00:00Z"} {
+		location = legs.LocationAt(at(value))
+		assert.Equal(t, models.AtAirport, location.Kind, value)
+		assert.Equal(t, "ATL", location.Airport, value)
+		assert.Equal(t, at("2026-10-01T14:00:00Z"), *location.Since)
+		assert.Equal(t, at("2026-10-01T18:00:00Z"), *location.Until)
+		assert.Equal(t, 1, location.PreviousLegIndex)
+		assert.Equal(t, 0, location.NextLegIndex)
+	}
+
+	// GSO - IND has no times so there's a gap between GSO and IND
+	location = legs.LocationAt(at("2026-10-02T08:00:00Z"))
+	assert.Equal(t, models.LocationUnknown, location.Kind)
+	assert.Equal(t, "", location.Airport)
+	assert.Equal(t, 0, location.PreviousLegIndex)
+	assert.Equal(t, 2, location.NextLegIndex)
+	assert.Contains(t, location.Detail, "GSO")
+
+	// after the last leg
+	location = legs.LocationAt(at("2026-10-03T08:00:00Z"))
+	assert.Equal(t, models.AtAirport, location.Kind)
+	assert.Equal(t, "EWR", location.Airport)
+	assert.Nil(t, location.Until)
+
+	// nothing to go on
+	location = legs[3:].LocationAt(at("2026-10-03T08:00:00Z"))
+	assert.Equal(t, models.LocationUnknown, location.Kind)
+	assert.Equal(t, -1, location.PreviousLegIndex)
+	assert.Equal(t, -1, location.NextLegIndex)
+}
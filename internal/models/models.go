@@ -7,4 +7,24 @@ type FlightOutput struct {
 	FinalArrivalAirport   string
 	Path                  string
 	ErrorInformation      string
+	// Itineraries is only filled in by FindItineraries
+	Itineraries []Itinerary `json:",omitempty"`
+	// Ambiguous is set by solvers that found more than one valid ordering of the flights
+	Ambiguous bool `json:",omitempty"`
+	// LegOrder is the position in the input of every flight in travel order,
+	// solvers that only find the start and end leave it empty
+	LegOrder []int `json:",omitempty"`
+	// TimingConflicts is filled in by Legs.Solve with every leg whose times contradict the path
+	TimingConflicts []TimingConflict `json:",omitempty"`
+	// Layovers is every layover between legs with times, see Legs.Layovers
+	Layovers []Layover `json:",omitempty"`
+	// OrderedLegs echoes the legs in travel order with everything given
+	// about them, only when the request gave any legs as objects
+	OrderedLegs Legs `json:",omitempty"`
+	// Normalizations is every airport code rewritten before solving, see Legs.Normalize
+	Normalizations []Normalization `json:",omitempty"`
+	// Distance is only filled in when asked for, see FlightsInput.Distance
+	Distance *TripDistance `json:",omitempty"`
+	// Timing is the block time of every flight and the elapsed trip time, see Legs.Timing
+	Timing *TripTiming `json:",omitempty"`
 }
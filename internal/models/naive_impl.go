@@ -2,11 +2,18 @@ package models
 
 import "fmt"
 
+// the naive implementation can only tell that there's no start or end,
+// not which flights are to blame
+var (
+	errNaiveNoStartFlight = fmt.Errorf("Unable to find starting flight, loop or invalid list provided: %w", ErrLoop)
+	errNaiveNoEndFlight   = fmt.Errorf("Unable to find ending flight, loop or invalid list provided: %w", ErrLoop)
+)
+
 // FindStartAndEndFlightNaive was my first/initial solution to this problem
 // It satisfied a decent chunk of test cases but I was unhappy that it
 // wasn't able to show me the ending path from A -> B
 // so then I wrote the linked_list implementation.
-func (fi FlightsInput) FindStartAndEndFlightNaive() (fo FlightOutput) {
+func (fi FlightsInput) FindStartAndEndFlightNaive() (fo FlightOutput, err error) {
 	startFlight := ""
 	endFlight := ""
 
@@ -14,21 +21,27 @@ func (fi FlightsInput) FindStartAndEndFlightNaive() (fo FlightOutput) {
 	startList, endList, err := fi.splitFlightsInput()
 	if err != nil {
 		fo.ErrorInformation = err.Error()
-		return fo
+		return fo, err
+	}
+	// no flights has no start either, but that isn't a loop
+	if len(fi) == 0 {
+		err = &EmptyInputError{}
+		fo.ErrorInformation = err.Error()
+		return fo, err
 	}
 
 	// find the first flight, it should not exist in the last flight list
 	startFlight = findItemNotInSecondList(startList, endList)
 	if startFlight == "" {
-		fo.ErrorInformation = "Unable to find starting flight, loop or invalid list provided."
-		return fo
+		fo.ErrorInformation = errNaiveNoStartFlight.Error()
+		return fo, errNaiveNoStartFlight
 	}
 
 	// find the last flight, it should not exist in the first flight list
 	endFlight = findItemNotInSecondList(endList, startList)
 	if endFlight == "" {
-		fo.ErrorInformation = "Unable to find ending flight, loop or invalid list provided."
-		return fo
+		fo.ErrorInformation = errNaiveNoEndFlight.Error()
+		return fo, errNaiveNoEndFlight
 	}
 
 	fo = FlightOutput{
@@ -36,19 +49,41 @@ func (fi FlightsInput) FindStartAndEndFlightNaive() (fo FlightOutput) {
 			startFlight,
 			endFlight,
 		},
-		FinalArrivalAirport:   startFlight,
-		FinalDepartureAirport: endFlight,
+		FinalDepartureAirport: startFlight,
+		FinalArrivalAirport:   endFlight,
 		ErrorInformation:      "",
 	}
+	fo.Path, fo.LegOrder = fi.naivePath(startFlight)
+
+	return fo, nil
+}
 
-	return fo
+// naivePath follows the flights from startFlight for as long as one leaves
+// the airport reached, a repeated departure takes the last flight from it
+func (fi FlightsInput) naivePath(startFlight string) (path string, legOrder []int) {
+	departures := make(map[string]int, len(fi))
+	for i, flightPair := range fi {
+		departures[flightPair[0]] = i
+	}
+
+	airports := []string{startFlight}
+	for airport := startFlight; len(legOrder) < len(fi); {
+		i, ok := departures[airport]
+		if !ok {
+			break
+		}
+		legOrder = append(legOrder, i)
+		airport = fi[i][1]
+		airports = append(airports, airport)
+	}
+	return concatPath(airports), legOrder
 }
 
 func (fi FlightsInput) splitFlightsInput() (startList, endList []string, err error) {
-	for _, flightPair := range fi {
+	for i, flightPair := range fi {
 		// ensure all flightPairs are exactly 2 long
 		if len(flightPair) != 2 {
-			return startList, endList, fmt.Errorf("Item %v does not have exactly 2 airports.", flightPair)
+			return startList, endList, &MalformedLegError{Index: i, Leg: flightPair}
 		}
 		for i, airport := range flightPair {
 			if i == 0 {
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// Normalization is an airport code that was rewritten before solving,
+// e.g. " ksfo" read as "SFO"
+type Normalization struct {
+	LegIndex int
+	// Field is from or to
+	Field      string
+	Original   string
+	Normalized string
+}
+
+// NormalizeAirportCode trims and upper-cases code and, when db knows it
+// as an ICAO code, swaps it for the airport's IATA code. db may be nil.
+func NormalizeAirportCode(code string, db *airports.DB) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if db == nil {
+		return code
+	}
+	if _, ok := db.ByIATA(code); ok {
+		return code
+	}
+	airport, ok := db.ByICAO(code)
+	if ok && airport.IATA != "" {
+		return airport.IATA
+	}
+	return code
+}
+
+// Normalize returns a copy of ls with every airport code run through
+// NormalizeAirportCode, and every code it changed. Malformed legs are
+// left as they are for the solvers to report.
+func (ls Legs) Normalize(db *airports.DB) (Legs, []Normalization) {
+	normalized := make(Legs, len(ls))
+	normalizations := []Normalization{}
+	for i, leg := range ls {
+		normalized[i] = leg
+		if len(leg.Pair()) != 2 {
+			continue
+		}
+
+		from := NormalizeAirportCode(leg.From, db)
+		if from != leg.From {
+			normalizations = append(normalizations, Normalization{LegIndex: i, Field: "from", Original: leg.From, Normalized: from})
+		}
+		to := NormalizeAirportCode(leg.To, db)
+		if to != leg.To {
+			normalizations = append(normalizations, Normalization{LegIndex: i, Field: "to", Original: leg.To, Normalized: to})
+		}
+
+		normalized[i].From = from
+		normalized[i].To = to
+		if leg.pair != nil {
+			normalized[i].pair = []string{from, to}
+		}
+	}
+	return normalized, normalizations
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAirportCode(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	db := airports.Default()
+	assert.Equal(t, "SFO", models.NormalizeAirportCode("sfo", db))
+	assert.Equal(t, "SFO", models.NormalizeAirportCode(" SFO ", db))
+	assert.Equal(t, "SFO", models.NormalizeAirportCode("KSFO", db))
+	assert.Equal(t, "SFO", models.NormalizeAirportCode("ksfo\t", db))
+	// unknown codes are only trimmed and upper-cased
+	assert.Equal(t, "HELLO", models.NormalizeAirportCode(" hello", db))
+	// without a db ICAO codes are left alone
+	assert.Equal(t, "KSFO", models.NormalizeAirportCode("ksfo", nil))
+}
+
+func TestNormalizeLegs(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sample := `[["sfo", " ATL"], {"from": "ATL", "to": "KEWR"}, ["EWR"]]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	normalized, normalizations := legs.Normalize(airports.Default())
+	assert.Equal(t, models.FlightsInput{{"SFO", "ATL"}, {"ATL", "EWR"}, {"EWR"}}, normalized.FlightsInput())
+	assert.Equal(t, []models.Normalization{
+		{LegIndex: 0, Field: "from", Original: "sfo", Normalized: "SFO"},
+		{LegIndex: 0, Field: "to", Original: " ATL", Normalized: "ATL"},
+		{LegIndex: 1, Field: "to", Original: "KEWR", Normalized: "EWR"},
+	}, normalizations)
+
+	// the legs given are left as they were
+	assert.Equal(t, "sfo", legs[0].From)
+
+	// the well formed legs now solve as one path
+	flightOutput, err := normalized[:2].Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	assert.Nil(t, err)
+	assert.Equal(t, "SFO - ATL - EWR", flightOutput.Path)
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"io"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// PathBuilder solves flights added one at a time the same way
+// FindStartAndEndFlightHashMap does. Malformed flights and repeated
+// airports are caught as soon as they're added, so a stream of flights
+// can stop being read at the first bad one.
+type PathBuilder struct {
+	fi FlightsInput
+	// airport -> index of the flight leaving it, and of the flight landing there
+	departureIndex map[string]int
+	arrivalIndex   map[string]int
+}
+
+// NewPathBuilder returns an empty PathBuilder
+func NewPathBuilder() *PathBuilder {
+	return &PathBuilder{
+		fi:             FlightsInput{},
+		departureIndex: make(map[string]int),
+		arrivalIndex:   make(map[string]int),
+	}
+}
+
+// Add adds the next flight, returning the same errors validateFlightsInput
+// would for it. A flight that's rejected isn't added.
+func (pb *PathBuilder) Add(flightPair []string) error {
+	i := len(pb.fi)
+	// ensure all flightPairs are exactly 2 long
+	if len(flightPair) != 2 {
+		return &MalformedLegError{Index: i, Leg: flightPair}
+	}
+
+	// ensure departure is unique
+	departure := flightPair[0]
+	first, ok := pb.departureIndex[departure]
+	if ok {
+		return &DuplicateDepartureError{Airport: departure, Indexes: []int{first, i}}
+	}
+
+	// ensure arrival is unique
+	arrival := flightPair[1]
+	first, ok = pb.arrivalIndex[arrival]
+	if ok {
+		return &DuplicateArrivalError{Airport: arrival, Indexes: []int{first, i}}
+	}
+
+	pb.departureIndex[departure] = i
+	pb.arrivalIndex[arrival] = i
+	pb.fi = append(pb.fi, flightPair)
+	return nil
+}
+
+// Len returns how many flights have been added
+func (pb *PathBuilder) Len() int {
+	return len(pb.fi)
+}
+
+// FlightsInput returns every flight added so far in the order they were added
+func (pb *PathBuilder) FlightsInput() FlightsInput {
+	return pb.fi
+}
+
+// Solve walks the flights added so far into a single path
+func (pb *PathBuilder) Solve() (fo FlightOutput, err error) {
+	// anything other than exactly one chain is either
+	// a loop or a set of disconnected chains
+	segments := findSegmentsIndexed(pb.fi, pb.departureIndex, pb.arrivalIndex)
+	if len(segments) != 1 || segments[0].Loop() {
+		err = segmentsError(segments)
+		fo.ErrorInformation = err.Error()
+		return fo, err
+	}
+	path := segments[0].Airports
+
+	startFlight := path[0]
+	fo.FinalDepartureAirport = startFlight
+
+	endFlight := path[len(path)-1]
+	fo.FinalArrivalAirport = endFlight
+
+	fo.CalculateResult = []string{
+		startFlight,
+		endFlight,
+	}
+
+	fo.Path = concatPath(path)
+	fo.LegOrder = segments[0].Indexes
+
+	return fo, nil
+}
+
+// SolveStream reads a JSON list of legs from r and solves it with a
+// PathBuilder without ever holding the whole list in memory, only the
+// airports of each leg are kept. Every code is normalized with
+// NormalizeAirportCode on the way in, db may be nil. It returns the flights
+// read, which stop at the first bad one, alongside the usual output.
+// Legs that can't be read give a *LegDecodeError.
+func SolveStream(r io.Reader, db *airports.DB) (fi FlightsInput, fo FlightOutput, err error) {
+	ld := NewLegDecoder(r)
+	pb := NewPathBuilder()
+	for {
+		leg, err := ld.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fo.ErrorInformation = err.Error()
+			return pb.FlightsInput(), fo, err
+		}
+
+		flightPair := leg.Pair()
+		if len(flightPair) == 2 {
+			from := NormalizeAirportCode(flightPair[0], db)
+			to := NormalizeAirportCode(flightPair[1], db)
+			if from != flightPair[0] {
+				fo.Normalizations = append(fo.Normalizations, Normalization{LegIndex: pb.Len(), Field: "from", Original: flightPair[0], Normalized: from})
+			}
+			if to != flightPair[1] {
+				fo.Normalizations = append(fo.Normalizations, Normalization{LegIndex: pb.Len(), Field: "to", Original: flightPair[1], Normalized: to})
+			}
+			flightPair = []string{from, to}
+		}
+
+		err = pb.Add(flightPair)
+		if err != nil {
+			fo.ErrorInformation = err.Error()
+			// keep the bad flight so the error's indexes point at something
+			return append(pb.FlightsInput(), flightPair), fo, err
+		}
+	}
+
+	normalizations := fo.Normalizations
+	fo, err = pb.Solve()
+	fo.Normalizations = normalizations
+	return pb.FlightsInput(), fo, err
+}
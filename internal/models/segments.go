@@ -0,0 +1,131 @@
+package models
+
+import "fmt"
+
+// Segment is one unbroken chain of flights found in a FlightsInput
+type Segment struct {
+	Departure string
+	Arrival   string
+	// Airports is every airport in the segment in travel order
+	Airports []string
+	// Indexes are the positions in the input of the segment's flights, in travel order
+	Indexes []int
+}
+
+// Loop reports whether the segment ends where it started
+func (s Segment) Loop() bool {
+	return s.Departure == s.Arrival
+}
+
+// findSegments splits flights into chains, it expects every departure and
+// every arrival to be unique, which validateFlightsInput guarantees.
+// Chains that start somewhere nobody arrives at come first in the order their
+// first flight was given in, any loops left over come after them.
+func findSegments(fi FlightsInput) (segments []Segment) {
+	// airport -> index of the flight leaving it, and of the flight landing there
+	departureIndex := make(map[string]int, len(fi))
+	arrivalIndex := make(map[string]int, len(fi))
+	for i, flightPair := range fi {
+		departureIndex[flightPair[0]] = i
+		arrivalIndex[flightPair[1]] = i
+	}
+	return findSegmentsIndexed(fi, departureIndex, arrivalIndex)
+}
+
+// findSegmentsIndexed is findSegments for callers that already
+// have every flight indexed by its departure and arrival airport
+func findSegmentsIndexed(fi FlightsInput, departureIndex, arrivalIndex map[string]int) (segments []Segment) {
+	visited := make([]bool, len(fi))
+	walk := func(start int) Segment {
+		segment := Segment{
+			Departure: fi[start][0],
+			Airports:  []string{fi[start][0]},
+		}
+		// loops end when we get back to a flight we've already walked
+		for i, ok := start, true; ok && !visited[i]; i, ok = departureIndex[fi[i][1]] {
+			visited[i] = true
+			segment.Indexes = append(segment.Indexes, i)
+			segment.Airports = append(segment.Airports, fi[i][1])
+		}
+		segment.Arrival = segment.Airports[len(segment.Airports)-1]
+		return segment
+	}
+
+	// chains start at a departure nobody arrives at
+	for i, flightPair := range fi {
+		_, ok := arrivalIndex[flightPair[0]]
+		if !ok {
+			segments = append(segments, walk(i))
+		}
+	}
+
+	// anything we haven't walked yet has to be part of a loop
+	for i := range fi {
+		if !visited[i] {
+			segments = append(segments, walk(i))
+		}
+	}
+
+	return segments
+}
+
+// Gap is where one chain of flights ends without the next one starting there
+type Gap struct {
+	// Arrival is where the earlier chain ends
+	Arrival string
+	// Departure is where the later chain starts
+	Departure string
+	// ArrivingLegIndex and DepartingLegIndex are the positions in the input
+	// of the earlier chain's last flight and the later chain's first
+	ArrivingLegIndex  int
+	DepartingLegIndex int
+}
+
+func (g Gap) String() string {
+	return fmt.Sprintf("Segment ending at %s and segment starting at %s are not connected.", g.Arrival, g.Departure)
+}
+
+// SuggestedLeg is the flight that would close the gap
+func (g Gap) SuggestedLeg() []string {
+	return []string{g.Arrival, g.Departure}
+}
+
+// findGaps returns the gaps between consecutive chains in segments, loops
+// are skipped since no added flight can join them to a chain
+func findGaps(segments []Segment) (gaps []Gap) {
+	var prev *Segment
+	for i := range segments {
+		segment := &segments[i]
+		if segment.Loop() {
+			continue
+		}
+		if prev != nil {
+			gaps = append(gaps, Gap{
+				Arrival:           prev.Arrival,
+				Departure:         segment.Departure,
+				ArrivingLegIndex:  prev.Indexes[len(prev.Indexes)-1],
+				DepartingLegIndex: segment.Indexes[0],
+			})
+		}
+		prev = segment
+	}
+	return gaps
+}
+
+// segmentsError turns the segments of an unsolvable FlightsInput into an
+// *EmptyInputError if there are none, a *LoopError if every segment is a
+// loop, or a *DisconnectedSegmentsError
+func segmentsError(segments []Segment) error {
+	if len(segments) == 0 {
+		return &EmptyInputError{}
+	}
+	for _, segment := range segments {
+		if !segment.Loop() {
+			return &DisconnectedSegmentsError{Segments: segments}
+		}
+	}
+	return &LoopError{
+		Airports: segments[0].Airports,
+		Indexes:  segments[0].Indexes,
+	}
+}
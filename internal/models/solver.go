@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sort"
+	"sync"
+)
+
+// names the built in solvers are registered under
+const (
+	NaiveSolver      = "naive"
+	LinkedListSolver = "linkedlist"
+	HashMapSolver    = "hashmap"
+	EulerianSolver   = "eulerian"
+)
+
+// PathSolver is anything that can turn an unordered FlightsInput
+// into a FlightOutput, every FindStartAndEndFlight* method is one.
+// Errors should be one of the types in errors.go where possible.
+type PathSolver interface {
+	Solve(fi FlightsInput) (FlightOutput, error)
+}
+
+// PathSolverFunc lets a plain function or a method expression like
+// FlightsInput.FindStartAndEndFlightHashMap be used as a PathSolver
+type PathSolverFunc func(fi FlightsInput) (FlightOutput, error)
+
+// Solve calls f(fi)
+func (f PathSolverFunc) Solve(fi FlightsInput) (FlightOutput, error) {
+	return f(fi)
+}
+
+// SolverRegistry keeps track of PathSolvers by name so they can be
+// picked per request, it's safe for concurrent use
+type SolverRegistry struct {
+	mu      sync.RWMutex
+	solvers map[string]PathSolver
+}
+
+// NewSolverRegistry returns an empty SolverRegistry
+func NewSolverRegistry() *SolverRegistry {
+	return &SolverRegistry{
+		solvers: make(map[string]PathSolver),
+	}
+}
+
+// NewDefaultSolverRegistry returns a SolverRegistry with every solver
+// in this package already registered
+func NewDefaultSolverRegistry() *SolverRegistry {
+	sr := NewSolverRegistry()
+	sr.Register(NaiveSolver, PathSolverFunc(FlightsInput.FindStartAndEndFlightNaive))
+	sr.Register(LinkedListSolver, PathSolverFunc(FlightsInput.FindStartAndEndFlightLinkedList))
+	sr.Register(HashMapSolver, PathSolverFunc(FlightsInput.FindStartAndEndFlightHashMap))
+	sr.Register(EulerianSolver, eulerianSolver{})
+	return sr
+}
+
+// Register adds solver under name, replacing any solver already using that name
+func (sr *SolverRegistry) Register(name string, solver PathSolver) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.solvers[name] = solver
+}
+
+// Get returns the solver registered under name
+func (sr *SolverRegistry) Get(name string) (solver PathSolver, ok bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	solver, ok = sr.solvers[name]
+	return solver, ok
+}
+
+// Names returns every registered solver name in sorted order
+func (sr *SolverRegistry) Names() []string {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	names := make([]string, 0, len(sr.solvers))
+	for name := range sr.solvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSolverRegistry(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sr := models.NewDefaultSolverRegistry()
+	assert.Equal(t, []string{"eulerian", "hashmap", "linkedlist", "naive"}, sr.Names())
+
+	fi := models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO", "IND"}, {"ATL", "GSO"}}
+	for _, name := range sr.Names() {
+		solver, ok := sr.Get(name)
+		assert.True(t, ok)
+
+		flightOutput, err := solver.Solve(fi)
+		// every solver should agree on the start and end
+		assert.Nil(t, err, name)
+		assert.Equal(t, "", flightOutput.ErrorInformation, name)
+		assert.Equal(t, []string{"SFO", "EWR"}, flightOutput.CalculateResult, name)
+		assert.Equal(t, "SFO", flightOutput.FinalDepartureAirport, name)
+		assert.Equal(t, "EWR", flightOutput.FinalArrivalAirport, name)
+		assert.Equal(t, "SFO - ATL - GSO - IND - EWR", flightOutput.Path, name)
+		assert.Equal(t, []int{1, 3, 2, 0}, flightOutput.LegOrder, name)
+	}
+}
+
+func TestSolverRegistryRegister(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	sr := models.NewSolverRegistry()
+	_, ok := sr.Get("fake")
+	assert.False(t, ok)
+
+	sr.Register("fake", models.PathSolverFunc(func(fi models.FlightsInput) (models.FlightOutput, error) {
+		return models.FlightOutput{Path: "fake"}, nil
+	}))
+
+	solver, ok := sr.Get("fake")
+	assert.True(t, ok)
+	flightOutput, err := solver.Solve(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "fake", flightOutput.Path)
+	assert.Equal(t, []string{"fake"}, sr.Names())
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+)
+
+// ResolveTimezones returns a copy of ls with every time given without an
+// offset put in the time zone of its airport, the departure airport's for
+// departures and the arrival airport's for arrivals. Times with an offset are
+// left as they were. Airports that db doesn't know, or that have no time
+// zone, give an *UnknownAirportsError naming the legs that needed them, db
+// may be nil when no times are local.
+// Local times that happen twice when the clocks go back resolve however
+// time.Date picks, which today is the first of the two.
+func (ls Legs) ResolveTimezones(db *airports.DB) (Legs, error) {
+	resolved := make(Legs, len(ls))
+	copy(resolved, ls)
+
+	unknown := &UnknownAirportsError{Curated: db.Curated()}
+	seen := make(map[string]bool)
+	location := airportLocations(db)
+
+	for i, leg := range ls {
+		blamed := false
+		if leg.departureLocal {
+			loc := location(leg.From)
+			if loc == nil {
+				blamed = true
+				if !seen[leg.From] {
+					seen[leg.From] = true
+					unknown.Airports = append(unknown.Airports, leg.From)
+				}
+			} else {
+				departure := inLocation(*leg.Departure, loc)
+				resolved[i].Departure = &departure
+				resolved[i].departureLocal = false
+			}
+		}
+		if leg.arrivalLocal {
+			loc := location(leg.To)
+			if loc == nil {
+				blamed = true
+				if !seen[leg.To] {
+					seen[leg.To] = true
+					unknown.Airports = append(unknown.Airports, leg.To)
+				}
+			} else {
+				arrival := inLocation(*leg.Arrival, loc)
+				resolved[i].Arrival = &arrival
+				resolved[i].arrivalLocal = false
+			}
+		}
+		if blamed {
+			unknown.Indexes = append(unknown.Indexes, i)
+		}
+	}
+
+	if len(unknown.Airports) > 0 {
+		return ls, unknown
+	}
+	return resolved, nil
+}
+
+// airportLocations returns a lookup of the time zone of an airport in db,
+// nil when db doesn't know the airport or its zone. Zones are only loaded
+// once per airport.
+func airportLocations(db *airports.DB) func(code string) *time.Location {
+	locations := make(map[string]*time.Location)
+	return func(code string) *time.Location {
+		loc, ok := locations[code]
+		if ok {
+			return loc
+		}
+		airport, ok := db.Lookup(code)
+		if ok && airport.Timezone != "" {
+			// a zone go doesn't know about is as good as none
+			loc, _ = time.LoadLocation(airport.Timezone)
+		}
+		locations[code] = loc
+		return loc
+	}
+}
+
+// inLocation reads the wall clock of t as a time in loc
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// LegTiming is how long one flight took from gate to gate
+type LegTiming struct {
+	// LegIndex is the position of the flight in the input
+	LegIndex  int
+	BlockTime Duration
+}
+
+// TripTiming is how long a trip took
+type TripTiming struct {
+	// Legs has every flight in travel order with both a departure and an arrival time
+	Legs []LegTiming
+	// BlockTime is the sum of the flights' block times
+	BlockTime Duration
+	// Elapsed is from the first departure to the last arrival, only
+	// when the first flight has a departure and the last an arrival
+	Elapsed *Duration `json:",omitempty"`
+}
+
+// Timing returns the block time of every flight in order and the time the
+// whole trip took, order being the LegOrder of a solved FlightOutput.
+// It's nil when no times are known. Times without an offset need
+// ResolveTimezones first, otherwise they're taken as UTC.
+func (ls Legs) Timing(order []int) *TripTiming {
+	if len(order) == 0 {
+		return nil
+	}
+
+	timing := &TripTiming{Legs: []LegTiming{}}
+	for _, i := range order {
+		leg := ls[i]
+		if leg.Departure == nil || leg.Arrival == nil {
+			continue
+		}
+		block := leg.Arrival.Sub(*leg.Departure)
+		timing.Legs = append(timing.Legs, LegTiming{LegIndex: i, BlockTime: Duration(block)})
+		timing.BlockTime += Duration(block)
+	}
+
+	first := ls[order[0]]
+	last := ls[order[len(order)-1]]
+	if first.Departure != nil && last.Arrival != nil {
+		elapsed := Duration(last.Arrival.Sub(*first.Departure))
+		timing.Elapsed = &elapsed
+	}
+
+	if len(timing.Legs) == 0 && timing.Elapsed == nil {
+		return nil
+	}
+	return timing
+}
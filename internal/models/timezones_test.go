@@ -0,0 +1,132 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTimezones(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// SYD -> LAX crosses the date line and lands "before" it takes off,
+	// LAX -> JFK gives its departure with an offset which is kept as is
+	sample := `[
+  {"from": "SYD", "to": "LAX", "departure": "2026-10-01T10:00", "arrival": "2026-10-01T06:00:00"},
+  {"from": "LAX", "to": "JFK", "departure": "2026-10-01T09:00:00-07:00", "arrival": "2026-10-01T17:30:00"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	resolved, err := legs.ResolveTimezones(airports.Default())
+	assert.Nil(t, err)
+
+	// Sydney is still on standard time, +10, and LA on daylight time, -7
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), resolved[0].Departure.UTC())
+	assert.Equal(t, time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC), resolved[0].Arrival.UTC())
+	assert.Equal(t, "Australia/Sydney", resolved[0].Departure.Location().String())
+	assert.Equal(t, time.Date(2026, 10, 1, 16, 0, 0, 0, time.UTC), resolved[1].Departure.UTC())
+	assert.Equal(t, time.Date(2026, 10, 1, 21, 30, 0, 0, time.UTC), resolved[1].Arrival.UTC())
+
+	flightOutput, err := resolved.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	assert.Nil(t, err)
+	assert.Equal(t, "SYD - LAX - JFK", flightOutput.Path)
+	assert.Empty(t, flightOutput.TimingConflicts)
+
+	timing := resolved.Timing(flightOutput.LegOrder)
+	assert.Equal(t, []models.LegTiming{
+		{LegIndex: 0, BlockTime: models.Duration(13 * time.Hour)},
+		{LegIndex: 1, BlockTime: models.Duration(5*time.Hour + 30*time.Minute)},
+	}, timing.Legs)
+	assert.Equal(t, models.Duration(18*time.Hour+30*time.Minute), timing.BlockTime)
+	// including the 3 hours in LAX
+	assert.Equal(t, models.Duration(21*time.Hour+30*time.Minute), *timing.Elapsed)
+
+	// left alone, the local times are read as UTC and the first leg lands before it departs
+	flightOutput, err = legs.Solve(models.PathSolverFunc(models.FlightsInput.FindStartAndEndFlightHashMap))
+	assert.Nil(t, err)
+	assert.Len(t, flightOutput.TimingConflicts, 1)
+}
+
+func TestResolveTimezonesDST(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// the US clocks go back at 2am on November 1st 2026, so the wall clocks
+	// say 6 hours once you account for the 3 hour difference but it's 7
+	sample := `[{"from": "JFK", "to": "LAX", "departure": "2026-10-31T23:00:00", "arrival": "2026-11-01T02:00:00"}]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	resolved, err := legs.ResolveTimezones(airports.Default())
+	assert.Nil(t, err)
+
+	timing := resolved.Timing([]int{0})
+	assert.Equal(t, models.Duration(7*time.Hour), timing.BlockTime)
+	assert.Equal(t, models.Duration(7*time.Hour), *timing.Elapsed)
+}
+
+func TestResolveTimezonesUnknownAirport(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// only local times need their airport to be known
+	sample := `[
+  {"from": "XYZ", "to": "LAX", "departure": "2026-10-01T10:00:00Z"},
+  {"from": "LAX", "to": "ABQ", "arrival": "2026-10-01T17:30:00"}
+  ]`
+	legs := models.Legs{}
+
+	err := json.Unmarshal([]byte(sample), &legs)
+	// ensure no Unmarshal error
+	assert.Nil(t, err)
+
+	_, err = legs.ResolveTimezones(airports.Default())
+	unknown := &models.UnknownAirportsError{}
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"ABQ"}, unknown.Airports)
+	assert.Equal(t, []int{1}, unknown.Indexes)
+
+	// without airports no local time can be placed
+	_, err = legs.ResolveTimezones(nil)
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"ABQ"}, unknown.Airports)
+	assert.False(t, unknown.Curated)
+}
+
+func TestInvalidLegTime(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(`[{"from": "SFO", "to": "LAX", "departure": "tomorrow"}]`), &legs)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `departure "tomorrow"`)
+}
+
+func TestTimingWithoutTimes(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(`[["SFO", "LAX"]]`), &legs)
+	assert.Nil(t, err)
+	assert.Nil(t, legs.Timing([]int{0}))
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError holds every problem Validate found, each one is one of the
+// error types in errors.go. errors.Is and errors.As match any of them.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors)+1)
+	messages = append(messages, fmt.Sprintf("Found %d problems in the given flight plan.", len(e.Errors)))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, " ")
+}
+
+// Is makes errors.Is match any of the problems found
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As makes errors.As match the first problem found of target's type
+func (e *ValidationError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validator is implemented by solvers that accept a different set of
+// flights than the hashmap and linkedlist solvers do, Legs.Validate uses
+// it in place of FlightsInput.Validate
+type Validator interface {
+	Validate(fi FlightsInput) error
+}
+
+// validationResult returns nil, the only problem found, or a *ValidationError
+func validationResult(problems []error) error {
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	}
+	return &ValidationError{Errors: problems}
+}
+
+// Validate checks fi the way the hashmap and linkedlist solvers do but
+// reports every problem instead of stopping at the first: every malformed
+// flight, every airport departed from or arrived at more than once with all
+// of its flights, every loop, and every chain that doesn't connect to the
+// others. Flights repeating an airport are left out of the loop and chain
+// checks after the first one, they're already reported as duplicates.
+// A single problem is returned as is, more than one as a *ValidationError.
+func (fi FlightsInput) Validate() error {
+	return fi.validate(false)
+}
+
+// ValidateItineraries is Validate for FindItineraries,
+// where flights that don't all connect aren't a problem
+func (fi FlightsInput) ValidateItineraries() error {
+	return fi.validate(true)
+}
+
+// validate is Validate, leaving out the disconnected chains check if split is set
+func (fi FlightsInput) validate(split bool) error {
+	if len(fi) == 0 {
+		return &EmptyInputError{}
+	}
+	problems := []error{}
+
+	// airport -> indexes of every flight leaving or landing there
+	departures := make(map[string][]int)
+	arrivals := make(map[string][]int)
+	// departure airports in the order they were first seen, same for arrivals
+	departureOrder := []string{}
+	arrivalOrder := []string{}
+
+	// the well formed flights with no repeated airports, for the loop and
+	// chain checks, and where each one is in fi
+	unique := FlightsInput{}
+	uniqueIndexes := []int{}
+
+	for i, flightPair := range fi {
+		if len(flightPair) != 2 {
+			problems = append(problems, &MalformedLegError{Index: i, Leg: flightPair})
+			continue
+		}
+
+		departure, arrival := flightPair[0], flightPair[1]
+		if len(departures[departure]) == 0 {
+			departureOrder = append(departureOrder, departure)
+		}
+		if len(arrivals[arrival]) == 0 {
+			arrivalOrder = append(arrivalOrder, arrival)
+		}
+		repeated := len(departures[departure]) > 0 || len(arrivals[arrival]) > 0
+		departures[departure] = append(departures[departure], i)
+		arrivals[arrival] = append(arrivals[arrival], i)
+
+		if !repeated {
+			unique = append(unique, flightPair)
+			uniqueIndexes = append(uniqueIndexes, i)
+		}
+	}
+
+	for _, airport := range departureOrder {
+		if len(departures[airport]) > 1 {
+			problems = append(problems, &DuplicateDepartureError{Airport: airport, Indexes: departures[airport]})
+		}
+	}
+	for _, airport := range arrivalOrder {
+		if len(arrivals[airport]) > 1 {
+			problems = append(problems, &DuplicateArrivalError{Airport: airport, Indexes: arrivals[airport]})
+		}
+	}
+
+	chains := []Segment{}
+	for _, segment := range findSegments(unique) {
+		// point back at the flights' positions in fi
+		for n, i := range segment.Indexes {
+			segment.Indexes[n] = uniqueIndexes[i]
+		}
+		if segment.Loop() {
+			problems = append(problems, &LoopError{Airports: segment.Airports, Indexes: segment.Indexes})
+			continue
+		}
+		chains = append(chains, segment)
+	}
+	if len(chains) > 1 && !split {
+		problems = append(problems, &DisconnectedSegmentsError{Segments: chains})
+	}
+
+	return validationResult(problems)
+}
+
+// Validate reports every problem that would stop ls being solved by solver,
+// the same way FlightsInput.Validate does. Legs that all have a departure
+// time are checked the way Solve would order them instead, falling back to
+// their airports when the times don't chain, and solvers that implement
+// Validator do their own checks.
+func (ls Legs) Validate(solver PathSolver) error {
+	fi := ls.FlightsInput()
+	byAirport := func() error {
+		validator, ok := solver.(Validator)
+		if ok {
+			return validator.Validate(fi)
+		}
+		return fi.Validate()
+	}
+	if !ls.timed() {
+		return byAirport()
+	}
+
+	problems := []error{}
+	for i, flightPair := range fi {
+		if len(flightPair) != 2 {
+			problems = append(problems, &MalformedLegError{Index: i, Leg: flightPair})
+		}
+	}
+	if len(problems) > 0 {
+		return validationResult(problems)
+	}
+	_, err := ls.solveChronologically()
+	if errors.Is(err, ErrDisconnectedSegments) && byAirport() == nil {
+		return nil
+	}
+	return err
+}
+
+// ValidateItineraries is Validate for Legs.FindItineraries. Legs that all
+// have a departure time can always be split in time order, so only
+// malformed legs are a problem for them.
+func (ls Legs) ValidateItineraries() error {
+	fi := ls.FlightsInput()
+	if !ls.timed() {
+		return fi.ValidateItineraries()
+	}
+
+	problems := []error{}
+	for i, flightPair := range fi {
+		if len(flightPair) != 2 {
+			problems = append(problems, &MalformedLegError{Index: i, Leg: flightPair})
+		}
+	}
+	return validationResult(problems)
+}
@@ -0,0 +1,105 @@
+package models_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	// a valid path has nothing to report
+	assert.Nil(t, models.FlightsInput{{"IND", "EWR"}, {"SFO", "ATL"}, {"GSO", "IND"}, {"ATL", "GSO"}}.Validate())
+
+	// a single problem comes back on its own
+	err := models.FlightsInput{{"IND", "EWR"}, {"SFO"}}.Validate()
+	malformed := &models.MalformedLegError{}
+	assert.True(t, errors.As(err, &malformed))
+	assert.Equal(t, 1, malformed.Index)
+}
+
+func TestValidateEveryProblem(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	fi := models.FlightsInput{
+		{"SFO"},         // 0 malformed
+		{"SFO", "ATL"},  // 1
+		{"ATL", "IND"},  // 2
+		{"ATL", "EWR"},  // 3 departs ATL again
+		{"ORD", "DEN"},  // 4 ORD -> DEN -> ORD loop
+		{"DEN", "ORD"},  // 5
+		{"SLC", "JFK"},  // 6 doesn't connect to SFO -> ATL -> IND
+		{"a", "b", "c"}, // 7 malformed
+		{"BOS", "IND"},  // 8 arrives IND again
+		{"PHX", "ATL"},  // 9 arrives ATL again
+	}
+	err := fi.Validate()
+
+	validation := &models.ValidationError{}
+	assert.True(t, errors.As(err, &validation))
+	assert.Equal(t, []error{
+		&models.MalformedLegError{Index: 0, Leg: []string{"SFO"}},
+		&models.MalformedLegError{Index: 7, Leg: []string{"a", "b", "c"}},
+		&models.DuplicateDepartureError{Airport: "ATL", Indexes: []int{2, 3}},
+		&models.DuplicateArrivalError{Airport: "ATL", Indexes: []int{1, 9}},
+		&models.DuplicateArrivalError{Airport: "IND", Indexes: []int{2, 8}},
+		&models.LoopError{Airports: []string{"ORD", "DEN", "ORD"}, Indexes: []int{4, 5}},
+		&models.DisconnectedSegmentsError{Segments: []models.Segment{
+			{Departure: "SFO", Arrival: "IND", Airports: []string{"SFO", "ATL", "IND"}, Indexes: []int{1, 2}},
+			{Departure: "SLC", Arrival: "JFK", Airports: []string{"SLC", "JFK"}, Indexes: []int{6}},
+		}},
+	}, validation.Errors)
+	assert.Contains(t, err.Error(), "Found 7 problems in the given flight plan.")
+
+	// every problem inside matches errors.Is
+	for _, target := range []error{models.ErrMalformedLeg, models.ErrDuplicateDeparture, models.ErrDuplicateArrival, models.ErrLoop, models.ErrDisconnectedSegments} {
+		assert.True(t, errors.Is(err, target))
+	}
+	assert.False(t, errors.Is(err, models.ErrUnbalancedAirports))
+
+	// split into itineraries the chains not connecting is fine
+	validation = &models.ValidationError{}
+	assert.True(t, errors.As(fi.ValidateItineraries(), &validation))
+	assert.Len(t, validation.Errors, 6)
+}
+
+func TestValidateLegs(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	registry := models.NewDefaultSolverRegistry()
+	hashMap, _ := registry.Get(models.HashMapSolver)
+	eulerian, _ := registry.Get(models.EulerianSolver)
+
+	// a round trip through ORD twice only the eulerian solver can take
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(`[["SFO", "ORD"], ["ORD", "JFK"], ["JFK", "ORD"], ["ORD", "SFO"]]`), &legs)
+	assert.Nil(t, err)
+	assert.NotNil(t, legs.Validate(hashMap))
+	assert.Nil(t, legs.Validate(eulerian))
+
+	// the eulerian solver reports every malformed leg and every unbalanced part
+	err = json.Unmarshal([]byte(`[["SFO", "ORD"], ["SFO", "JFK"], ["IND", "EWR"], ["IND", "ATL"]]`), &legs)
+	assert.Nil(t, err)
+	validation := &models.ValidationError{}
+	assert.True(t, errors.As(legs.Validate(eulerian), &validation))
+	assert.Len(t, validation.Errors, 2)
+	assert.True(t, errors.Is(validation.Errors[0], models.ErrUnbalancedAirports))
+
+	// timed legs are checked in departure order, so round trips are fine
+	err = json.Unmarshal([]byte(`[
+  {"from": "ORD", "to": "SFO", "departure": "2026-10-03T18:00:00Z"},
+  {"from": "SFO", "to": "ORD", "departure": "2026-10-01T08:00:00Z"}
+  ]`), &legs)
+	assert.Nil(t, err)
+	assert.Nil(t, legs.Validate(hashMap))
+}
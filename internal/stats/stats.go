@@ -0,0 +1,309 @@
+// Package stats aggregates solved itineraries into route network
+// statistics over a sliding window of time.
+package stats
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// defaults for a new Aggregator
+const (
+	DefaultResolution = time.Minute
+	DefaultRetention  = 24 * time.Hour
+)
+
+// route is a directed pair of airports
+type route struct {
+	from, to string
+}
+
+// bucket is everything recorded during one Resolution sized slice of time
+type bucket struct {
+	start       time.Time
+	itineraries int
+	legs        int
+	// origin -> destination of whole itineraries
+	pairs map[route]int
+	// every individual leg flown
+	flights map[route]int
+	// airport -> itineraries that connected there
+	connections map[string]int
+}
+
+func newBucket(start time.Time) *bucket {
+	return &bucket{
+		start:       start,
+		pairs:       make(map[route]int),
+		flights:     make(map[route]int),
+		connections: make(map[string]int),
+	}
+}
+
+// Aggregator counts solved itineraries in Resolution sized buckets and
+// drops them once they're older than Retention, so memory stays bounded
+// however many itineraries are recorded. It's safe for concurrent use.
+type Aggregator struct {
+	// Resolution is how finely windows are cut, a window can be off by up to one Resolution
+	Resolution time.Duration
+	// Retention is the longest window that can be asked for
+	Retention time.Duration
+	// Now is the clock itineraries are recorded and windows are measured by
+	Now func() time.Time
+
+	mu sync.Mutex
+	// buckets oldest first
+	buckets []*bucket
+}
+
+// NewAggregator returns an empty Aggregator with DefaultResolution and DefaultRetention
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		Resolution: DefaultResolution,
+		Retention:  DefaultRetention,
+		Now:        time.Now,
+	}
+}
+
+// Record counts one solved itinerary, order is the position in fi of every
+// flight in travel order as in FlightOutput.LegOrder. Itineraries without
+// an order, like the naive solver's, can't be counted and are skipped.
+func (a *Aggregator) Record(fi models.FlightsInput, order []int) {
+	if len(order) == 0 {
+		return
+	}
+	path := make([][]string, 0, len(order))
+	for _, i := range order {
+		if i < 0 || i >= len(fi) || len(fi[i]) != 2 {
+			return
+		}
+		path = append(path, fi[i])
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.Now()
+	a.prune(now)
+
+	start := now.Truncate(a.Resolution)
+	if len(a.buckets) == 0 || a.buckets[len(a.buckets)-1].start.Before(start) {
+		a.buckets = append(a.buckets, newBucket(start))
+	}
+	b := a.buckets[len(a.buckets)-1]
+
+	b.itineraries++
+	b.legs += len(path)
+	b.pairs[route{from: path[0][0], to: path[len(path)-1][1]}]++
+	// an itinerary only connects at an airport once however often it passes through
+	connected := make(map[string]bool, len(path))
+	for n, flightPair := range path {
+		b.flights[route{from: flightPair[0], to: flightPair[1]}]++
+		if n > 0 && !connected[flightPair[0]] {
+			connected[flightPair[0]] = true
+			b.connections[flightPair[0]]++
+		}
+	}
+}
+
+// RecordOutput records every itinerary in fo, one for each of Itineraries
+// when the legs were split or the one the whole output describes otherwise
+func (a *Aggregator) RecordOutput(fi models.FlightsInput, fo models.FlightOutput) {
+	if len(fo.Itineraries) > 0 {
+		for _, itinerary := range fo.Itineraries {
+			a.Record(fi, itinerary.LegIndexes)
+		}
+		return
+	}
+	a.Record(fi, fo.LegOrder)
+}
+
+// prune drops buckets that have fallen out of Retention, a.mu must be held
+func (a *Aggregator) prune(now time.Time) {
+	cutoff := now.Add(-a.Retention).Truncate(a.Resolution)
+	n := 0
+	for n < len(a.buckets) && a.buckets[n].start.Before(cutoff) {
+		n++
+	}
+	a.buckets = a.buckets[n:]
+}
+
+// window adds up every bucket in the last window, a window of zero or
+// more than Retention is all of Retention and is returned as such
+func (a *Aggregator) window(window time.Duration) (total *bucket, since time.Time, clamped time.Duration) {
+	if window <= 0 || window > a.Retention {
+		window = a.Retention
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.Now()
+	a.prune(now)
+
+	since = now.Add(-window).Truncate(a.Resolution)
+	total = newBucket(since)
+	for _, b := range a.buckets {
+		if b.start.Before(since) {
+			continue
+		}
+		total.itineraries += b.itineraries
+		total.legs += b.legs
+		for r, count := range b.pairs {
+			total.pairs[r] += count
+		}
+		for r, count := range b.flights {
+			total.flights[r] += count
+		}
+		for airport, count := range b.connections {
+			total.connections[airport] += count
+		}
+	}
+	return total, since, window
+}
+
+// RouteCount is how many times a route was flown
+type RouteCount struct {
+	From  string
+	To    string
+	Count int
+}
+
+// RouteStats are the busiest routes over a window
+type RouteStats struct {
+	Window models.Duration
+	// Since is when the window started
+	Since       time.Time
+	Itineraries int
+	// AveragePathLength is the average number of legs in an itinerary
+	AveragePathLength float64
+	// TopPairs are the most common origins and destinations of whole itineraries
+	TopPairs []RouteCount
+	// TopLegs are the most flown individual legs
+	TopLegs []RouteCount
+}
+
+// Routes returns the limit busiest pairs and legs over the last window,
+// a limit of zero or less returns all of them
+func (a *Aggregator) Routes(window time.Duration, limit int) RouteStats {
+	total, since, window := a.window(window)
+	stats := RouteStats{
+		Window:      models.Duration(window),
+		Since:       since,
+		Itineraries: total.itineraries,
+		TopPairs:    topRoutes(total.pairs, limit),
+		TopLegs:     topRoutes(total.flights, limit),
+	}
+	if total.itineraries > 0 {
+		stats.AveragePathLength = float64(total.legs) / float64(total.itineraries)
+	}
+	return stats
+}
+
+// topRoutes sorts counts busiest first, ties alphabetically, and keeps the first limit
+func topRoutes(counts map[route]int, limit int) []RouteCount {
+	routes := make([]RouteCount, 0, len(counts))
+	for r, count := range counts {
+		routes = append(routes, RouteCount{From: r.from, To: r.to, Count: count})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Count != routes[j].Count {
+			return routes[i].Count > routes[j].Count
+		}
+		if routes[i].From != routes[j].From {
+			return routes[i].From < routes[j].From
+		}
+		return routes[i].To < routes[j].To
+	})
+	if limit > 0 && len(routes) > limit {
+		routes = routes[:limit]
+	}
+	return routes
+}
+
+// AirportCount is how one airport figures in the network
+type AirportCount struct {
+	Airport    string
+	Departures int
+	Arrivals   int
+	// Degree is how many different airports it has flights to or from
+	Degree int
+	// Connections is how many itineraries changed planes there
+	Connections int
+	// Centrality is the share of every itinerary that connected there,
+	// the airports with the highest are the network's hubs
+	Centrality float64
+}
+
+// AirportStats are the most central airports over a window
+type AirportStats struct {
+	Window models.Duration
+	// Since is when the window started
+	Since       time.Time
+	Itineraries int
+	Airports    []AirportCount
+}
+
+// Airports returns the limit most central airports over the last window,
+// ties go to the better connected airport. A limit of zero or less
+// returns all of them.
+func (a *Aggregator) Airports(window time.Duration, limit int) AirportStats {
+	total, since, window := a.window(window)
+
+	counts := make(map[string]*AirportCount)
+	count := func(airport string) *AirportCount {
+		c, ok := counts[airport]
+		if !ok {
+			c = &AirportCount{Airport: airport}
+			counts[airport] = c
+		}
+		return c
+	}
+	neighbors := make(map[route]bool)
+	for r, flown := range total.flights {
+		count(r.from).Departures += flown
+		count(r.to).Arrivals += flown
+		// flights both ways between two airports are one neighbor
+		for _, pair := range []route{{r.from, r.to}, {r.to, r.from}} {
+			if !neighbors[pair] {
+				neighbors[pair] = true
+				count(pair.from).Degree++
+			}
+		}
+	}
+	for airport, connections := range total.connections {
+		c := count(airport)
+		c.Connections = connections
+		c.Centrality = float64(connections) / float64(total.itineraries)
+	}
+
+	airports := make([]AirportCount, 0, len(counts))
+	for _, c := range counts {
+		airports = append(airports, *c)
+	}
+	sort.Slice(airports, func(i, j int) bool {
+		if airports[i].Centrality != airports[j].Centrality {
+			return airports[i].Centrality > airports[j].Centrality
+		}
+		if airports[i].Degree != airports[j].Degree {
+			return airports[i].Degree > airports[j].Degree
+		}
+		flightsI := airports[i].Departures + airports[i].Arrivals
+		flightsJ := airports[j].Departures + airports[j].Arrivals
+		if flightsI != flightsJ {
+			return flightsI > flightsJ
+		}
+		return airports[i].Airport < airports[j].Airport
+	})
+	if limit > 0 && len(airports) > limit {
+		airports = airports[:limit]
+	}
+
+	return AirportStats{
+		Window:      models.Duration(window),
+		Since:       since,
+		Itineraries: total.itineraries,
+		Airports:    airports,
+	}
+}
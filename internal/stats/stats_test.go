@@ -0,0 +1,61 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	a := stats.NewAggregator()
+	a.Retention = 2 * time.Hour
+	a.Now = func() time.Time { return now }
+
+	// SFO - ATL - GSO, given out of order
+	a.Record(models.FlightsInput{{"ATL", "GSO"}, {"SFO", "ATL"}}, []int{1, 0})
+	// IND - ATL - GSO - EWR
+	a.Record(models.FlightsInput{{"IND", "ATL"}, {"ATL", "GSO"}, {"GSO", "EWR"}}, []int{0, 1, 2})
+	// no order to go on, so not counted
+	a.Record(models.FlightsInput{{"SFO", "ATL"}}, nil)
+
+	// an hour later, split into two itineraries
+	now = now.Add(time.Hour)
+	a.RecordOutput(models.FlightsInput{{"SFO", "ATL"}, {"IND", "EWR"}}, models.FlightOutput{
+		Itineraries: []models.Itinerary{{LegIndexes: []int{0}}, {LegIndexes: []int{1}}},
+	})
+
+	routes := a.Routes(0, 2)
+	assert.Equal(t, models.Duration(2*time.Hour), routes.Window)
+	assert.Equal(t, 4, routes.Itineraries)
+	assert.Equal(t, 7.0/4.0, routes.AveragePathLength)
+	assert.Equal(t, []stats.RouteCount{{From: "IND", To: "EWR", Count: 2}, {From: "SFO", To: "ATL", Count: 1}}, routes.TopPairs)
+	assert.Equal(t, []stats.RouteCount{{From: "ATL", To: "GSO", Count: 2}, {From: "SFO", To: "ATL", Count: 2}}, routes.TopLegs)
+
+	airports := a.Airports(0, 0)
+	assert.Len(t, airports.Airports, 5)
+	assert.Equal(t, stats.AirportCount{Airport: "ATL", Departures: 2, Arrivals: 3, Degree: 3, Connections: 2, Centrality: 0.5}, airports.Airports[0])
+	assert.Equal(t, stats.AirportCount{Airport: "GSO", Departures: 1, Arrivals: 2, Degree: 2, Connections: 1, Centrality: 0.25}, airports.Airports[1])
+
+	// only the last half hour
+	routes = a.Routes(30*time.Minute, 0)
+	assert.Equal(t, 2, routes.Itineraries)
+	assert.Equal(t, 1.0, routes.AveragePathLength)
+
+	// the first itineraries age out of retention
+	now = now.Add(90 * time.Minute)
+	routes = a.Routes(0, 0)
+	assert.Equal(t, 2, routes.Itineraries)
+	now = now.Add(time.Hour)
+	routes = a.Routes(0, 0)
+	assert.Equal(t, 0, routes.Itineraries)
+	assert.Equal(t, 0.0, routes.AveragePathLength)
+	assert.Empty(t, routes.TopLegs)
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// passengersBucket holds a bucket per passenger, each leg in it is keyed by
+// its position so the bucket's order is the order the legs were appended in
+var passengersBucket = []byte("passengers")
+
+// BoltStore is a Store kept in a bbolt file, so it survives restarts
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens or creates a BoltStore at path, only one
+// process can have the file open at a time
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(passengersBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Append adds legs to the end of passenger's legs in a single transaction
+func (bs *BoltStore) Append(passenger string, legs models.Legs) error {
+	// an empty bucket would list the passenger without any legs
+	if len(legs) == 0 {
+		return nil
+	}
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(passengersBucket).CreateBucketIfNotExists([]byte(passenger))
+		if err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			data, err := json.Marshal(leg)
+			if err != nil {
+				return err
+			}
+			sequence, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, sequence)
+			err = bucket.Put(key, data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Legs returns every leg appended for passenger in order
+func (bs *BoltStore) Legs(passenger string) (legs models.Legs, err error) {
+	err = bs.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(passengersBucket).Bucket([]byte(passenger))
+		if bucket == nil {
+			return ErrNotFound
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			leg := models.Leg{}
+			err := json.Unmarshal(data, &leg)
+			if err != nil {
+				return fmt.Errorf("unable to read a leg of passenger %s: %w", passenger, err)
+			}
+			legs = append(legs, leg)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(legs) == 0 {
+		return nil, ErrNotFound
+	}
+	return legs, nil
+}
+
+// Passengers returns every passenger with legs in sorted order,
+// which is the order bbolt keeps them in
+func (bs *BoltStore) Passengers() (passengers []string, err error) {
+	err = bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(passengersBucket).ForEach(func(name, _ []byte) error {
+			passengers = append(passengers, string(name))
+			return nil
+		})
+	})
+	return passengers, err
+}
+
+// Close closes the bbolt file
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
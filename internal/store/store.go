@@ -0,0 +1,79 @@
+// Package store keeps each passenger's legs between requests
+// so they can be sent as they arrive instead of all at once.
+package store
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+)
+
+// ErrNotFound is returned for a passenger without any legs
+var ErrNotFound = errors.New("passenger not found")
+
+// Store keeps every passenger's legs in the order they were appended,
+// implementations are safe for concurrent use
+type Store interface {
+	// Append adds legs to the end of passenger's legs, starting them if there aren't any yet
+	Append(passenger string, legs models.Legs) error
+	// Legs returns every leg appended for passenger in order, or ErrNotFound
+	Legs(passenger string) (models.Legs, error)
+	// Passengers returns every passenger with legs in sorted order
+	Passengers() ([]string, error)
+	Close() error
+}
+
+// MemoryStore is a Store that lasts as long as the process does
+type MemoryStore struct {
+	mu         sync.RWMutex
+	passengers map[string]models.Legs
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		passengers: make(map[string]models.Legs),
+	}
+}
+
+// Append adds legs to the end of passenger's legs
+func (ms *MemoryStore) Append(passenger string, legs models.Legs) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.passengers[passenger] = append(ms.passengers[passenger], legs...)
+	return nil
+}
+
+// Legs returns a copy of every leg appended for passenger
+func (ms *MemoryStore) Legs(passenger string) (models.Legs, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	stored, ok := ms.passengers[passenger]
+	if !ok || len(stored) == 0 {
+		return nil, ErrNotFound
+	}
+	legs := make(models.Legs, len(stored))
+	copy(legs, stored)
+	return legs, nil
+}
+
+// Passengers returns every passenger with legs in sorted order
+func (ms *MemoryStore) Passengers() ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	passengers := make([]string, 0, len(ms.passengers))
+	for passenger, legs := range ms.passengers {
+		if len(legs) > 0 {
+			passengers = append(passengers, passenger)
+		}
+	}
+	sort.Strings(passengers)
+	return passengers, nil
+}
+
+// Close does nothing, everything in a MemoryStore is lost when the process exits
+func (ms *MemoryStore) Close() error {
+	return nil
+}
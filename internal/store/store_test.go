@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLegs(t *testing.T, body string) models.Legs {
+	legs := models.Legs{}
+	err := json.Unmarshal([]byte(body), &legs)
+	assert.Nil(t, err)
+	return legs
+}
+
+// testStore runs the same checks against every Store implementation
+func testStore(t *testing.T, s store.Store) {
+	_, err := s.Legs("nobody")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	passengers, err := s.Passengers()
+	assert.Nil(t, err)
+	assert.Empty(t, passengers)
+
+	// legs come back in the order they were appended, across appends
+	assert.Nil(t, s.Append("P2", mustLegs(t, `[["SFO", "ATL"]]`)))
+	assert.Nil(t, s.Append("P1", mustLegs(t, `[["IND", "EWR"]]`)))
+	assert.Nil(t, s.Append("P2", mustLegs(t, `[["IND", "SFO"], ["GSO", "IND"]]`)))
+
+	legs, err := s.Legs("P2")
+	assert.Nil(t, err)
+	assert.Equal(t, models.FlightsInput{{"SFO", "ATL"}, {"IND", "SFO"}, {"GSO", "IND"}}, legs.FlightsInput())
+
+	passengers, err = s.Passengers()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"P1", "P2"}, passengers)
+
+	// appending nothing doesn't start a passenger
+	assert.Nil(t, s.Append("P3", models.Legs{}))
+	_, err = s.Legs("P3")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestMemoryStore(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	s := store.NewMemoryStore()
+	defer s.Close()
+	testStore(t, s)
+
+	// callers can't change what's stored through the returned legs
+	legs, err := s.Legs("P1")
+	assert.Nil(t, err)
+	legs[0].From = "XXX"
+	legs, err = s.Legs("P1")
+	assert.Nil(t, err)
+	assert.Equal(t, "IND", legs[0].From)
+}
+
+func TestBoltStore(t *testing.T) {
+	// this tells go that this test can run in Parallel
+	// with other t.parallel enabled unit tests
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "passengers.db")
+	s, err := store.OpenBolt(path)
+	assert.Nil(t, err)
+	testStore(t, s)
+	assert.Nil(t, s.Append("P4", mustLegs(t, `[{"from": "SFO", "to": "ATL", "departure": "2026-10-01T08:00", "arrival": "2026-10-01T16:05:00-04:00", "flight": "DL 123"}]`)))
+	assert.Nil(t, s.Close())
+
+	// everything is still there after reopening the file
+	s, err = store.OpenBolt(path)
+	assert.Nil(t, err)
+	defer s.Close()
+
+	legs, err := s.Legs("P2")
+	assert.Nil(t, err)
+	assert.Equal(t, models.FlightsInput{{"SFO", "ATL"}, {"IND", "SFO"}, {"GSO", "IND"}}, legs.FlightsInput())
+
+	legs, err = s.Legs("P4")
+	assert.Nil(t, err)
+	assert.Len(t, legs, 1)
+	assert.Equal(t, "DL 123", legs[0].Flight)
+	assert.Equal(t, "2026-10-01T20:05:00Z", legs[0].Arrival.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	// local times stay local
+	out, err := json.Marshal(legs[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"departure":"2026-10-01T08:00:00"`)
+
+	passengers, err := s.Passengers()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"P1", "P2", "P4"}, passengers)
+}
@@ -1,15 +1,131 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/SophisticaSean/flight_path_calculator/internal/airports"
 	"github.com/SophisticaSean/flight_path_calculator/internal/controllers"
+	"github.com/SophisticaSean/flight_path_calculator/internal/graph"
+	"github.com/SophisticaSean/flight_path_calculator/internal/models"
+	"github.com/SophisticaSean/flight_path_calculator/internal/stats"
+	"github.com/SophisticaSean/flight_path_calculator/internal/store"
 )
 
 func main() {
-	http.Handle("/calculate", http.HandlerFunc(controllers.CalculateHandler))
+	defaultSolver := flag.String("solver", models.HashMapSolver, "solver used when a request doesn't pick one with ?solver= or the X-Flight-Solver header")
+	connectionRulesFile := flag.String("connection-rules", "", "JSON file with the minimum connection time, per airport overrides and stopover threshold")
+	minimumConnection := flag.Duration("mct", 0, "minimum connection time at airports without their own, overrides -connection-rules")
+	stopoverThreshold := flag.Duration("stopover", 0, "layovers at least this long are stopovers, overrides -connection-rules")
+	airportsFile := flag.String("airports", "", "OpenFlights airports.dat to use instead of the embedded airports")
+	strictAirports := flag.Bool("strict-airports", false, "reject unknown airport codes unless a request asks for ?airports=lenient")
+	storeFile := flag.String("store", "", "bbolt file to keep passengers' legs in, they're only kept in memory without one")
+	collectStats := flag.Bool("stats", false, "count solved itineraries and serve them from /stats/routes and /stats/airports")
+	statsRetention := flag.Duration("stats-retention", stats.DefaultRetention, "longest window /stats can be asked for, older itineraries are forgotten")
+	routesFile := flag.String("routes", "", "OpenFlights routes.dat or CSV schedule of the route network served by /route, reloaded on SIGHUP")
+	flag.Parse()
+
+	airportDB := airports.Default()
+	if *airportsFile != "" {
+		var err error
+		airportDB, err = airports.LoadFile(*airportsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var network *graph.Network
+	if *routesFile != "" {
+		if airportDB.Curated() {
+			fmt.Println("only the curated embedded airports are available, routes using any other airport will be skipped, use -airports with a full airports.dat to load them")
+		}
+		var err error
+		network, err = graph.OpenNetwork(*routesFile, airportDB)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printNetwork(network.Graph())
+		go reloadOnHangup(network)
+	}
+
+	connectionRules := models.DefaultConnectionRules()
+	if *connectionRulesFile != "" {
+		var err error
+		connectionRules, err = models.LoadConnectionRules(*connectionRulesFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *minimumConnection > 0 {
+		connectionRules.MinimumConnection = models.Duration(*minimumConnection)
+	}
+	if *stopoverThreshold > 0 {
+		connectionRules.StopoverThreshold = models.Duration(*stopoverThreshold)
+	}
+
+	solvers := models.NewDefaultSolverRegistry()
+	if _, ok := solvers.Get(*defaultSolver); !ok {
+		fmt.Fprintf(os.Stderr, "unknown solver %q, available solvers are: %v\n", *defaultSolver, solvers.Names())
+		os.Exit(1)
+	}
+
+	calculateController := controllers.NewCalculateController(solvers, *defaultSolver)
+	calculateController.ConnectionRules = connectionRules
+	calculateController.Airports = airportDB
+	calculateController.StrictAirports = *strictAirports
+
+	var passengerStore store.Store = store.NewMemoryStore()
+	if *storeFile != "" {
+		var err error
+		passengerStore, err = store.OpenBolt(*storeFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	defer passengerStore.Close()
+
+	http.Handle("/calculate", calculateController)
+	http.Handle("/calculate/batch", controllers.NewBatchController(calculateController))
+	http.Handle(controllers.PassengersPath, controllers.NewPassengerController(passengerStore, calculateController))
+	if network != nil {
+		http.Handle("/route", controllers.NewRouteController(network, airportDB))
+	}
+	if *collectStats {
+		calculateController.Stats = stats.NewAggregator()
+		calculateController.Stats.Retention = *statsRetention
+		statsController := controllers.NewStatsController(calculateController.Stats)
+		http.HandleFunc("/stats/routes", statsController.Routes)
+		http.HandleFunc("/stats/airports", statsController.Airports)
+	}
 	fmt.Println("listening on localhost:8080/calculate")
 	// ignoring the error value returned by ListenAndServe
 	_ = http.ListenAndServe(":8080", nil)
 }
+
+// printNetwork says how much of the route network was loaded
+func printNetwork(g *graph.Graph) {
+	fmt.Printf("loaded %d routes between %d airports, skipped %d\n", g.Len(), len(g.Airports()), len(g.Skipped()))
+}
+
+// reloadOnHangup reloads the route network every time the process gets a SIGHUP,
+// a file that fails to load keeps the network that was already loaded
+func reloadOnHangup(network *graph.Network) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	for range hangups {
+		err := network.Reload()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to reload routes, keeping the last ones loaded: %v\n", err)
+			continue
+		}
+		printNetwork(network.Graph())
+	}
+}